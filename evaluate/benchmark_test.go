@@ -0,0 +1,138 @@
+package evaluate
+
+import (
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
+)
+
+// The benchmarks are in the package, so that the tree evaluator can be run on a tree that is built once,
+// like the VM runs a program that is compiled once.
+
+const benchmarkExpression = "x^3 - 2 * x^2 + sin(x) * {x if x > 0; -x otherwise}"
+
+func benchmarkDomain() types.Interval[float64] {
+	return *real.NewInterval(-10, 0.02, 10)
+}
+
+func BenchmarkEvaluateTree(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	tree, err := buildTree(parsed, real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		domain := benchmarkDomain()
+		result := []float64{}
+		var current float64
+		lookup := func(string) (float64, bool) {
+			return current, true
+		}
+		done := false
+		for current = domain.Start; !done; current, done = domain.Next(current) {
+			value, err := tree.eval(lookup, real.Real)
+			if err != nil {
+				b.Fatal(err)
+			}
+			result = append(result, value)
+		}
+	}
+}
+
+func BenchmarkEvaluateVM(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		program, err := Compile(parsed, []string{"x"}, real.Real)
+		if err != nil {
+			b.Fatal(err)
+		}
+		vm := NewVM(program)
+		domain := benchmarkDomain()
+		result := []float64{}
+		variables := make([]float64, 1)
+		done := false
+		for current := domain.Start; !done; current, done = domain.Next(current) {
+			variables[0] = current
+			value, err := vm.Run(variables)
+			if err != nil {
+				b.Fatal(err)
+			}
+			result = append(result, value)
+		}
+	}
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := Evaluate(parsed, benchmarkDomain(), real.Real); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEvaluateInto(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	program, err := Compile(parsed, []string{"x"}, real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	vm := NewVM(program)
+	dst := []float64{}
+	for i := 0; i < b.N; i++ {
+		if dst, err = vm.EvaluateInto(dst, benchmarkDomain()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOnceTree(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	tree, err := buildTree(parsed, real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	lookup := func(string) (float64, bool) {
+		return 1.5, true
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := tree.eval(lookup, real.Real); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOnceVM(b *testing.B) {
+	parsed, err := parsexp.Parse(benchmarkExpression, "x", real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	program, err := Compile(parsed, []string{"x"}, real.Real)
+	if err != nil {
+		b.Fatal(err)
+	}
+	vm := NewVM(program)
+	variables := []float64{1.5}
+	for i := 0; i < b.N; i++ {
+		if _, err := vm.Run(variables); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package evaluate
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
+)
+
+// Opcode is the operation of an instruction.
+type Opcode uint8
+
+// The possible opcodes
+const (
+	PushConst  Opcode = iota // Pushes the constant Arg
+	LoadVar                  // Pushes the value of the variable Arg
+	Call                     // Pops the arguments of the keyword Arg, and pushes the keyword applied to them
+	Jump                     // Continues at the instruction Target
+	JumpUnless               // Pops a value, and continues at the instruction Target unless the condition of keyword Arg holds for it
+	EvalTree                 // Pushes the value of the expression tree Arg, for keywords that bind variables
+)
+
+// Instruction is a single step of a compiled expression.
+type Instruction struct {
+	Op     Opcode
+	Arg    int32 // The index of a constant, variable, keyword or tree
+	Target int32 // The index of the instruction a jump continues at
+}
+
+// A keyword used by a program, with the data needed to call it
+type compiledKeyword[T any] struct {
+	keyword   types.Keyword
+	arity     int
+	apply     func(...T) (T, error)
+	condition func(T) bool
+	validate  func(T) error
+}
+
+// Program is an expression compiled to bytecode, which is run by a VM.
+// Only the branch taken by a conditional is run.
+type Program[T any] struct {
+	Code      []Instruction
+	Constants []T
+	Variables []string // The names of the variables, in the order their values are given to a VM
+	keywords  []compiledKeyword[T]
+	trees     []*node[T]
+	depth     int  // The largest number of values on the stack
+	shared    bool // Every variable takes the single value given to a VM
+	m         *types.MathGroup[T]
+}
+
+// Returns the number of values given to a VM that runs the program
+func (p *Program[T]) values() int {
+	if p.shared {
+		return 1
+	}
+	return len(p.Variables)
+}
+
+// Compiles expression trees into a program, keeping track of the depth of the stack
+type compiler[T any] struct {
+	program  *Program[T]
+	keywords map[types.Keyword]int32
+	depth    int
+}
+
+func (c *compiler[T]) emit(op Opcode, arg int, push int) int {
+	c.program.Code = append(c.program.Code, Instruction{Op: op, Arg: int32(arg)})
+	c.depth += push
+	if c.depth > c.program.depth {
+		c.program.depth = c.depth
+	}
+	return len(c.program.Code) - 1
+}
+
+// Returns the index of a keyword in the program, adding it if it is not there yet
+func (c *compiler[T]) keyword(keyword types.Keyword) int {
+	if index, ok := c.keywords[keyword]; ok {
+		return int(index)
+	}
+	keywordData, _ := c.program.m.Data(keyword)
+	c.keywords[keyword] = int32(len(c.program.keywords))
+	c.program.keywords = append(c.program.keywords, compiledKeyword[T]{
+		keyword:   keyword,
+		arity:     c.program.m.Arity(keyword),
+		apply:     keywordData.Apply,
+		condition: keywordData.Condition,
+		validate:  keywordData.Validate,
+	})
+	return len(c.program.keywords) - 1
+}
+
+// Compiles the tree rooted at n, whose value is pushed onto the stack
+func (c *compiler[T]) compile(n *node[T]) error {
+	p := c.program
+	switch n.tokenType {
+	case types.Value:
+		c.emit(PushConst, len(p.Constants), 1)
+		p.Constants = append(p.Constants, n.value)
+		return nil
+	case types.Variable:
+		if p.shared {
+			c.emit(LoadVar, 0, 1)
+			return nil
+		}
+		index := indexOf(p.Variables, n.token)
+		if index < 0 {
+			return errors.New("variable " + n.token + " has no value")
+		}
+		c.emit(LoadVar, index, 1)
+		return nil
+	}
+
+	if variables, _ := p.m.Binding(n.keyword); variables > 0 {
+		c.emit(EvalTree, len(p.trees), 1)
+		p.trees = append(p.trees, n)
+		return nil
+	}
+	if p.m.Condition(n.keyword) != nil {
+		if err := c.compile(n.args[0]); err != nil {
+			return err
+		}
+		jumpUnless := c.emit(JumpUnless, c.keyword(n.keyword), -1)
+		if err := c.compile(n.args[1]); err != nil {
+			return err
+		}
+		// Only one of the branches is run, so the second starts with the depth the first did
+		jump := c.emit(Jump, 0, -1)
+		p.Code[jumpUnless].Target = int32(len(p.Code))
+		if err := c.compile(n.args[2]); err != nil {
+			return err
+		}
+		p.Code[jump].Target = int32(len(p.Code))
+		return nil
+	}
+
+	for _, arg := range n.args {
+		if err := c.compile(arg); err != nil {
+			return err
+		}
+	}
+	c.emit(Call, c.keyword(n.keyword), 1-len(n.args))
+	return nil
+}
+
+// Compile compiles an expression in postfix notation into a program. Each variable in the expression must be
+// one of the given variable names, whose values are given to a VM in the same order.
+func Compile[T any](expression parsexp.ParsedExpression, variableNames []string, m *types.MathGroup[T]) (*Program[T], error) {
+	return compile(expression, &Program[T]{Variables: variableNames, m: m})
+}
+
+// Compiles an expression in which every variable takes the single value given to a VM, as in Evaluate.
+func compileShared[T any](expression parsexp.ParsedExpression, m *types.MathGroup[T]) (*Program[T], error) {
+	return compile(expression, &Program[T]{shared: true, m: m})
+}
+
+func compile[T any](expression parsexp.ParsedExpression, program *Program[T]) (*Program[T], error) {
+	tree, err := buildTree(expression, program.m)
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler[T]{program: program, keywords: map[types.Keyword]int32{}}
+	if err := c.compile(tree); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// VM runs a program, with a stack that is allocated once and reused by every run.
+// A VM must not be used by more than one goroutine at a time.
+type VM[T any] struct {
+	program *Program[T]
+	stack   []T
+	point   []T // The value of a program's only variable, when evaluating at many points
+}
+
+// NewVM constructs a VM that runs the given program.
+func NewVM[T any](program *Program[T]) *VM[T] {
+	return &VM[T]{program: program, stack: make([]T, program.depth), point: make([]T, 1)}
+}
+
+// Run runs the program with the given values of its variables, and returns the value of the expression.
+func (vm *VM[T]) Run(variables []T) (T, error) {
+	var zero T
+	p := vm.program
+	if len(variables) != p.values() {
+		return zero, errors.New("program has " + strconv.Itoa(p.values()) + " variables, but got " + strconv.Itoa(len(variables)))
+	}
+	stack := vm.stack
+	top := 0
+	for pc := 0; pc < len(p.Code); pc++ {
+		instruction := p.Code[pc]
+		switch instruction.Op {
+		case PushConst:
+			stack[top] = p.Constants[instruction.Arg]
+			top++
+		case LoadVar:
+			stack[top] = variables[instruction.Arg]
+			top++
+		case Call:
+			k := &p.keywords[instruction.Arg]
+			value, err := k.apply(stack[top-k.arity : top]...)
+			if err != nil {
+				return zero, err
+			}
+			top -= k.arity - 1
+			stack[top-1] = value
+		case Jump:
+			pc = int(instruction.Target) - 1
+		case JumpUnless:
+			top--
+			k := &p.keywords[instruction.Arg]
+			if k.validate != nil {
+				if err := k.validate(stack[top]); err != nil {
+					return zero, err
+				}
+			}
+			if !k.condition(stack[top]) {
+				pc = int(instruction.Target) - 1
+			}
+		case EvalTree:
+			value, err := p.trees[instruction.Arg].eval(func(name string) (T, bool) {
+				if p.shared {
+					return variables[0], true
+				}
+				if index := indexOf(p.Variables, name); index >= 0 {
+					return variables[index], true
+				}
+				return zero, false
+			}, p.m)
+			if err != nil {
+				return zero, err
+			}
+			stack[top] = value
+			top++
+		}
+	}
+	return stack[0], nil
+}
+
+// EvaluateInto runs a program of one variable at each point of the domain, writing the values into dst.
+// dst is only grown if it is too short, and is returned resliced to the number of points. Once dst is long
+// enough, no memory is allocated unless the program evaluates a keyword that binds variables, or its keywords do.
+func (vm *VM[T]) EvaluateInto(dst []T, domain types.Interval[T]) ([]T, error) {
+	dst = dst[:0]
+	if vm.program.values() != 1 {
+		return dst, errors.New("program must have a single variable")
+	}
+	done := false
+	for current := domain.Start; !done; current, done = domain.Next(current) {
+		vm.point[0] = current
+		value, err := vm.Run(vm.point)
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, value)
+	}
+	return dst, nil
+}
+
+// EvaluateBatch runs a program of one variable at each value of xs, writing the value at xs[i] into dst[i].
+// dst must be at least as long as xs. Like EvaluateInto, it does not allocate memory for each value.
+func (vm *VM[T]) EvaluateBatch(xs []T, dst []T) error {
+	if vm.program.values() != 1 {
+		return errors.New("program must have a single variable")
+	}
+	if len(dst) < len(xs) {
+		return errors.New("dst has " + strconv.Itoa(len(dst)) + " values, but xs has " + strconv.Itoa(len(xs)))
+	}
+	for i, x := range xs {
+		vm.point[0] = x
+		value, err := vm.Run(vm.point)
+		if err != nil {
+			return err
+		}
+		dst[i] = value
+	}
+	return nil
+}
+
+// Returns the index of a name in names, or -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -4,53 +4,94 @@ package evaluate
 import (
 	"errors"
 
-	"github.com/karalabe/cookiejar/collections/stack"
 	"github.com/yasteen/go-parse/parsexp"
 	"github.com/yasteen/go-parse/types"
 )
 
-// For now, assume there's only one variable
-// TODO: Finish implementation for any number of variables, and rethink output type.
-
 // Evaluate evaluates the given expression within the given domain.
+// Every variable in the expression takes the value of the current point.
 func Evaluate[T any](expression parsexp.ParsedExpression, domain types.Interval[T], m *types.MathGroup[T]) ([]T, error) {
-	result := []T{}
-	done := false
-	for current := domain.Start; !done; current, done = domain.Next(current) {
-		val, err := Once(expression, current, m)
-		if err != nil {
-			return result, err
-		}
-		result = append(result, val)
+	return EvaluateInto([]T{}, expression, domain, m)
+}
+
+// EvaluateInto evaluates the given expression within the given domain like Evaluate, writing the values into dst.
+// dst is only grown if it is too short, and is returned resliced to the number of points. To evaluate the same
+// expression repeatedly without allocating, compile it once and use VM.EvaluateInto.
+func EvaluateInto[T any](dst []T, expression parsexp.ParsedExpression, domain types.Interval[T], m *types.MathGroup[T]) ([]T, error) {
+	program, err := compileShared(expression, m)
+	if err != nil {
+		return dst[:0], err
 	}
-	return result, nil
+	return NewVM(program).EvaluateInto(dst, domain)
 }
 
 // Once evaluates the given expression using a given variable under the context of the given mathematical group.
+// Every variable in the expression takes the given value.
 func Once[T any](expression parsexp.ParsedExpression, variable T, m *types.MathGroup[T]) (T, error) {
-	values := stack.New()
-	for _, t := range expression {
-		tokenType, keyword := m.StringToTokenType(t)
-		var value T
-		switch tokenType {
-		case types.Value:
-			value, _ = m.GetValue(t)
-		case types.Variable:
-			value = variable
-		case types.Operator:
-			val2 := values.Pop().(T)
-			val1 := values.Pop().(T)
-			value = m.ApplyKeyword(keyword, val1, val2)
-		case types.SingleFunction:
-			val := values.Pop().(T)
-			value = m.ApplyKeyword(keyword, val)
-		default:
-			return variable, errors.New("invalid token")
+	return once(expression, func(string) (T, bool) { return variable, true }, m)
+}
+
+// OnceVars evaluates the given expression, looking up the value of each variable by name.
+func OnceVars[T any](expression parsexp.ParsedExpression, variables map[string]T, m *types.MathGroup[T]) (T, error) {
+	return once(expression, func(name string) (T, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}, m)
+}
+
+func once[T any](expression parsexp.ParsedExpression, lookup func(string) (T, bool), m *types.MathGroup[T]) (T, error) {
+	var zero T
+	tree, err := buildTree(expression, m)
+	if err != nil {
+		return zero, err
+	}
+	return tree.eval(lookup, m)
+}
+
+// Script evaluates the bindings of a script in order, and then its result. Each expression can use the given
+// variables, and the names bound before it, which shadow variables of the same name. The value bound to each
+// name is returned along with the result, so that intermediate values can be inspected. Names bound with let
+// are only used by their own statement, and are not returned.
+func Script[T any](script *parsexp.Script, variables map[string]T, m *types.MathGroup[T]) (T, map[string]T, error) {
+	var zero T
+	bindings := make(map[string]T, len(script.Bindings))
+	lookup := func(name string) (T, bool) {
+		if value, ok := bindings[name]; ok {
+			return value, true
+		}
+		value, ok := variables[name]
+		return value, ok
+	}
+	for _, binding := range script.Bindings {
+		value, err := let(binding.Lets, binding.Expression, lookup, m)
+		if err != nil {
+			return zero, bindings, errors.New("evaluating " + binding.Name + ": " + err.Error())
 		}
-		values.Push(value)
+		bindings[binding.Name] = value
 	}
-	if values.Size() != 1 {
-		return variable, errors.New("expression is invalid")
+	result, err := let(script.Lets, script.Result, lookup, m)
+	return result, bindings, err
+}
+
+// Evaluates the bindings made with let in order, and then an expression that can use them along with lookup
+func let[T any](lets []parsexp.Binding, expression parsexp.ParsedExpression, lookup func(string) (T, bool), m *types.MathGroup[T]) (T, error) {
+	if len(lets) == 0 {
+		return once(expression, lookup, m)
+	}
+	var zero T
+	local := make(map[string]T, len(lets))
+	scoped := func(name string) (T, bool) {
+		if value, ok := local[name]; ok {
+			return value, true
+		}
+		return lookup(name)
+	}
+	for _, binding := range lets {
+		value, err := let(binding.Lets, binding.Expression, scoped, m)
+		if err != nil {
+			return zero, errors.New("evaluating " + binding.Name + ": " + err.Error())
+		}
+		local[binding.Name] = value
 	}
-	return values.Pop().(T), nil
+	return once(expression, scoped, m)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/yasteen/go-parse/evaluate"
 	"github.com/yasteen/go-parse/mathgroups/real"
 	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
 )
 
 func TestEvaluateOnce(t *testing.T) {
@@ -27,7 +28,19 @@ func TestEvaluateOnce(t *testing.T) {
 		512,
 		t,
 	)
-
+	testEvaluateOnceHelper(
+		parsexp.ParsedExpression{"x", "!", "2", "*", "not"},
+		3,
+		0,
+		t,
+	)
+	testEvaluateOnceHelper(
+		parsexp.ParsedExpression{"x", "%", "x", "!", "+"},
+		4,
+		24.04,
+		t,
+	)
+	testEvaluateStringHelper("2^3^x", 2, 64, t)
 }
 
 func testEvaluateOnceHelper(expression parsexp.ParsedExpression, variable float64, expected float64, t *testing.T) {
@@ -41,3 +54,231 @@ func testEvaluateOnceHelper(expression parsexp.ParsedExpression, variable float6
 		t.Error("EvaluateOnce failed. Expected:", expected, "Result:", value)
 	}
 }
+
+func TestEvaluateGrid(t *testing.T) {
+	expression, err := parsexp.ParseVars("x * 10 + y", []string{"x", "y"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := types.NewGrid(
+		types.Axis[float64]{Name: "x", Interval: *real.NewInterval(0, 1, 2)},
+		types.Axis[float64]{Name: "y", Interval: *real.NewInterval(0, 1, 1)},
+	)
+	result, err := evaluate.EvaluateGrid(expression, *grid, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Shape) != 2 || result.Shape[0] != 3 || result.Shape[1] != 2 {
+		t.Fatal("EvaluateGrid produced the wrong shape:", result.Shape)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			expected := float64(i*10 + j)
+			if value := result.At(i, j); value != expected {
+				t.Error("EvaluateGrid failed at", i, j, "- Expected:", expected, "Result:", value)
+			}
+			if value := result.Rows()[i][j]; value != expected {
+				t.Error("Rows failed at", i, j, "- Expected:", expected, "Result:", value)
+			}
+		}
+	}
+}
+
+func testEvaluateStringHelper(expression string, variable float64, expected float64, t *testing.T) {
+	parsed, err := parsexp.Parse(expression, "x", real.Real)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	testEvaluateOnceHelper(parsed, variable, expected, t)
+}
+
+func TestEvaluateConditional(t *testing.T) {
+	testEvaluateStringHelper("if(x == 0, 0, 1 / x)", 0, 0, t)
+	testEvaluateStringHelper("if(x == 0, 0, 1 / x)", 4, 0.25, t)
+	testEvaluateStringHelper("if(x < 0, -x, x)", -3, 3, t)
+	testEvaluateStringHelper("x > 0 && x < 1", 0.5, 1, t)
+	testEvaluateStringHelper("x > 0 && x < 1 || x == 5", 2, 0, t)
+	testEvaluateStringHelper("not x >= 1", 2, 0, t)
+	testEvaluateStringHelper("-x^2 + 2 * -x", 3, -15, t)
+	testEvaluateStringHelper("{x^2 if x < 0; x otherwise}", -3, 9, t)
+	testEvaluateStringHelper("{x^2 if x < 0; x otherwise}", 2, 2, t)
+	testEvaluateStringHelper("{1 / x if x != 0; {0 if x < 1; 1 otherwise} otherwise}", 0, 0, t)
+}
+
+func TestEvaluateSeries(t *testing.T) {
+	testEvaluateStringHelper("sum(k, 1, 10, k^2)", 0, 385, t)
+	testEvaluateStringHelper("prod(k, 1, x, (x + k))", 3, 120, t)
+	testEvaluateStringHelper("sum(x, 1, 3, x) + x", 10, 16, t)
+	testEvaluateStringHelper("sum(k, 1, 3, sum(j, 1, k, j * k))", 0, 25, t)
+	testEvaluateStringHelper("sum(k, x, 1, k) + prod(k, x, 1, k)", 2, 1, t)
+
+	for _, expression := range []string{"sum(k, 1, x / 2, k)", "sum(k, 1, 3)", "sum(2, 1, 3, k)", "k + sum(k, 1, 3, k)", "sum(k, k, 3, k)"} {
+		parsed, err := parsexp.Parse(expression, "x", real.Real)
+		if err == nil {
+			_, err = evaluate.OnceVars(parsed, map[string]float64{"x": 3}, real.Real)
+		}
+		if err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+}
+
+func TestEvaluateScript(t *testing.T) {
+	script, err := parsexp.ParseScript("a = 2*x; b = a^2 + 1; # comment\n b / a", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, bindings, err := evaluate.Script(script, map[string]float64{"x": 2}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 17.0/4 || bindings["a"] != 4 || bindings["b"] != 17 {
+		t.Error("Script failed. Expected 4.25, a = 4 and b = 17. Result:", value, bindings)
+	}
+
+	script, err = parsexp.ParseScript("x = x + 1; let y = x^2 in y + sum(x, 1, y, x)", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _, err := evaluate.Script(script, map[string]float64{"x": 1}, real.Real); err != nil || value != 14 {
+		t.Error("Script failed. Expected 14. Result:", value, err)
+	}
+
+	for s, expected := range map[string]float64{
+		"y = 5; let y = 2 in a = y; y + a":           7,
+		"a = let y = 2 in y + 1; a":                  3,
+		"let y = let x = 3 in x * x in y + x":        10,
+		"let y = x in a = let z = y + 1 in z * y; a": 2,
+	} {
+		script, err := parsexp.ParseScript(s, []string{"x"}, real.Real)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, bindings, err := evaluate.Script(script, map[string]float64{"x": 1}, real.Real)
+		if err != nil || value != expected {
+			t.Error("Script", s, "failed. Expected", expected, "Result:", value, err)
+		}
+		if _, ok := bindings["z"]; ok || len(bindings) > 2 {
+			t.Error("Script", s, "returned names bound with let:", bindings)
+		}
+	}
+
+	script, err = parsexp.ParseScript("a = 1 / (x - 1); a", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := evaluate.Script(script, map[string]float64{"x": 1}, real.Real); err == nil {
+		t.Error("Expected an error evaluating a binding")
+	}
+}
+
+func TestCompile(t *testing.T) {
+	for _, expression := range []string{
+		"x",
+		"x * 10 + y",
+		"-x^2 + 2 * -y",
+		"if(x == 0, 0, 1 / x)",
+		"{x^2 if x < y; {x if x == 2; y otherwise} otherwise} + 1",
+		"x > 0 && x < 1 || y == 5",
+		"sum(k, 1, x, k * y) + prod(k, 1, 3, k)",
+	} {
+		parsed, err := parsexp.ParseVars(expression, []string{"x", "y"}, real.Real)
+		if err != nil {
+			t.Fatal(err)
+		}
+		program, err := evaluate.Compile(parsed, []string{"x", "y"}, real.Real)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm := evaluate.NewVM(program)
+		for _, point := range [][]float64{{0, 0}, {2, 5}, {-3, 1}, {4, -2}} {
+			expected, err := evaluate.OnceVars(parsed, map[string]float64{"x": point[0], "y": point[1]}, real.Real)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := vm.Run(point)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != expected {
+				t.Error("VM failed on", expression, "at", point, "Expected:", expected, "Result:", value)
+			}
+		}
+	}
+
+	parsed, _ := parsexp.Parse("1 / x", "x", real.Real)
+	program, err := evaluate.Compile(parsed, []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := evaluate.NewVM(program).Run([]float64{0}); err == nil {
+		t.Error("Expected an error dividing by zero")
+	}
+	if _, err := evaluate.NewVM(program).Run([]float64{1, 2}); err == nil {
+		t.Error("Expected an error running with the wrong number of variables")
+	}
+	if _, err := evaluate.Compile(parsed, []string{"y"}, real.Real); err == nil {
+		t.Error("Expected an error compiling a variable that has no value")
+	}
+}
+
+func TestEvaluateInto(t *testing.T) {
+	parsed, err := parsexp.Parse("x^2 + {x if x > 0; -x otherwise}", "x", real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]float64, 0, 8)
+	values, err := evaluate.EvaluateInto(dst, parsed, *real.NewInterval(-1, 0.5, 1), real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{2, 0.75, 0, 0.75, 2}
+	if len(values) != len(expected) || &values[0] != &dst[:1][0] {
+		t.Fatal("EvaluateInto failed. Expected the values in dst. Result:", values)
+	}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Error("EvaluateInto failed. Expected:", expected, "Result:", values)
+		}
+	}
+
+	program, err := evaluate.Compile(parsed, []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := evaluate.NewVM(program)
+	domain := *real.NewInterval(-10, 0.01, 10)
+	allocs := testing.AllocsPerRun(10, func() {
+		if dst, err = vm.EvaluateInto(dst, domain); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Error("EvaluateInto allocated", allocs, "times")
+	}
+
+	xs := []float64{-2, -1, 0, 1, 2}
+	batch := make([]float64, len(xs))
+	allocs = testing.AllocsPerRun(10, func() {
+		if err := vm.EvaluateBatch(xs, batch); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Error("EvaluateBatch allocated", allocs, "times")
+	}
+	if batch[0] != 6 || batch[2] != 0 || batch[4] != 6 {
+		t.Error("EvaluateBatch failed. Expected [6 2 0 2 6]. Result:", batch)
+	}
+	if err := vm.EvaluateBatch(xs, batch[:2]); err == nil {
+		t.Error("Expected an error when dst is shorter than xs")
+	}
+
+	parsed, _ = parsexp.ParseVars("x + y", []string{"x", "y"}, real.Real)
+	program, _ = evaluate.Compile(parsed, []string{"x", "y"}, real.Real)
+	if err := evaluate.NewVM(program).EvaluateBatch(xs, batch); err == nil {
+		t.Error("Expected an error batching a program of two variables")
+	}
+}
@@ -0,0 +1,84 @@
+package evaluate
+
+import (
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
+)
+
+// GridResult holds the values of an expression over a Grid.
+// Values are stored in row-major order, so the last axis varies fastest.
+type GridResult[T any] struct {
+	Shape  []int
+	Values []T
+}
+
+// At returns the value at the given index along each axis.
+func (r *GridResult[T]) At(indices ...int) T {
+	if len(indices) != len(r.Shape) {
+		panic("Wrong number of indices")
+	}
+	offset := 0
+	for i, index := range indices {
+		if index < 0 || index >= r.Shape[i] {
+			panic("Index out of range")
+		}
+		offset = offset*r.Shape[i] + index
+	}
+	return r.Values[offset]
+}
+
+// Rows splits the values into rows along the last axis.
+// For a two-dimensional grid, this is a rows × columns table.
+func (r *GridResult[T]) Rows() [][]T {
+	columns := r.Shape[len(r.Shape)-1]
+	rows := [][]T{}
+	for start := 0; start < len(r.Values); start += columns {
+		rows = append(rows, r.Values[start:start+columns])
+	}
+	return rows
+}
+
+// EvaluateGrid evaluates the given expression at every point of the given grid.
+// Each axis name is used as a variable name.
+func EvaluateGrid[T any](expression parsexp.ParsedExpression, grid types.Grid[T], m *types.MathGroup[T]) (*GridResult[T], error) {
+	names := grid.Names()
+	points := grid.Points()
+	result := &GridResult[T]{Shape: make([]int, len(points))}
+	size := 1
+	for i, p := range points {
+		result.Shape[i] = len(p)
+		size *= len(p)
+	}
+	result.Values = make([]T, 0, size)
+
+	tree, err := buildTree(expression, m)
+	if err != nil {
+		return result, err
+	}
+	variables := make(map[string]T, len(names))
+	lookup := func(name string) (T, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+	indices := make([]int, len(points))
+	for n := 0; n < size; n++ {
+		for i, index := range indices {
+			variables[names[i]] = points[i][index]
+		}
+		val, err := tree.eval(lookup, m)
+		if err != nil {
+			return result, err
+		}
+		result.Values = append(result.Values, val)
+
+		// Advance the indices like an odometer, starting from the last axis.
+		for i := len(indices) - 1; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(points[i]) {
+				break
+			}
+			indices[i] = 0
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package evaluate
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/karalabe/cookiejar/collections/stack"
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
+)
+
+// A node of an expression tree. Keywords have their arguments as children.
+type node[T any] struct {
+	token     string
+	tokenType types.TokenType
+	keyword   types.Keyword
+	value     T // For values
+	args      []*node[T]
+}
+
+// Builds an expression tree from an expression in postfix notation.
+func buildTree[T any](expression parsexp.ParsedExpression, m *types.MathGroup[T]) (*node[T], error) {
+	nodes := stack.New()
+	for _, t := range expression {
+		tokenType, keyword := m.StringToTokenType(t)
+		n := &node[T]{token: t, tokenType: tokenType, keyword: keyword}
+		switch tokenType {
+		case types.Value:
+			n.value, _ = m.GetValue(t)
+		case types.Variable:
+		case types.Operator, types.SingleFunction:
+			n.args = make([]*node[T], m.Arity(keyword))
+			if nodes.Size() < len(n.args) {
+				return nil, errors.New("expression is invalid")
+			}
+			for i := len(n.args) - 1; i >= 0; i-- {
+				n.args[i] = nodes.Pop().(*node[T])
+			}
+			variables, _ := m.Binding(keyword)
+			for i := 0; i < variables; i++ {
+				if n.args[i].tokenType != types.Variable {
+					return nil, errors.New("argument " + strconv.Itoa(i+1) + " of " + t + " must be a variable")
+				}
+			}
+		default:
+			return nil, errors.New("invalid token")
+		}
+		nodes.Push(n)
+	}
+	if nodes.Size() != 1 {
+		return nil, errors.New("expression is invalid")
+	}
+	return nodes.Pop().(*node[T]), nil
+}
+
+// Evaluates the tree rooted at n, looking up the value of each variable by name.
+// Only the branch taken by a conditional is evaluated, the body of a series is evaluated once for each index,
+// and the body of a keyword that binds variables is evaluated whenever it is called.
+func (n *node[T]) eval(lookup func(string) (T, bool), m *types.MathGroup[T]) (T, error) {
+	var zero T
+	switch n.tokenType {
+	case types.Value:
+		return n.value, nil
+	case types.Variable:
+		value, ok := lookup(n.token)
+		if !ok {
+			return zero, errors.New("variable " + n.token + " has no value")
+		}
+		return value, nil
+	}
+
+	if condition := m.Condition(n.keyword); condition != nil {
+		value, err := n.args[0].eval(lookup, m)
+		if err != nil {
+			return zero, err
+		}
+		if validate := m.Validate(n.keyword); validate != nil {
+			if err := validate(value); err != nil {
+				return zero, err
+			}
+		}
+		if condition(value) {
+			return n.args[1].eval(lookup, m)
+		}
+		return n.args[2].eval(lookup, m)
+	}
+
+	if index := m.Index(n.keyword); index != nil {
+		return n.evalSeries(index, lookup, m)
+	}
+	if lambda := m.Lambda(n.keyword); lambda != nil {
+		return n.evalLambda(lambda, lookup, m)
+	}
+
+	args := make([]T, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(lookup, m)
+		if err != nil {
+			return zero, err
+		}
+		args[i] = value
+	}
+	return m.ApplyKeyword(n.keyword, args...)
+}
+
+// Evaluates a series, with its index variable bound to each integer from the first to the last value in its body.
+func (n *node[T]) evalSeries(index func(T) (int, bool), lookup func(string) (T, bool), m *types.MathGroup[T]) (T, error) {
+	var zero T
+	bounds := [2]int{}
+	for i := range bounds {
+		value, err := n.args[i+1].eval(lookup, m)
+		if err != nil {
+			return zero, err
+		}
+		bound, ok := index(value)
+		if !ok {
+			return zero, errors.New("the bounds of " + n.token + " must be integers")
+		}
+		bounds[i] = bound
+	}
+
+	name := n.args[0].token
+	var current T
+	bodyLookup := func(variable string) (T, bool) {
+		if variable == name {
+			return current, true
+		}
+		return lookup(variable)
+	}
+	result, err := m.ApplyKeyword(n.keyword)
+	if err != nil {
+		return zero, err
+	}
+	for k := bounds[0]; k <= bounds[1]; k++ {
+		var ok bool
+		if current, ok = m.GetValue(strconv.Itoa(k)); !ok {
+			return zero, errors.New("the index of " + n.token + " can not be " + strconv.Itoa(k))
+		}
+		value, err := n.args[3].eval(bodyLookup, m)
+		if err != nil {
+			return zero, err
+		}
+		if result, err = m.ApplyKeyword(n.keyword, result, value); err != nil {
+			return zero, err
+		}
+	}
+	return result, nil
+}
+
+// Evaluates a keyword that binds variables, with a function that evaluates its body with the variables bound.
+func (n *node[T]) evalLambda(lambda func(func(...T) (T, error), ...T) (T, error), lookup func(string) (T, bool), m *types.MathGroup[T]) (T, error) {
+	var zero T
+	variables, body := m.Binding(n.keyword)
+	params := make([]T, len(n.args)-body-1)
+	for i := range params {
+		value, err := n.args[body+1+i].eval(lookup, m)
+		if err != nil {
+			return zero, err
+		}
+		params[i] = value
+	}
+
+	values := make([]T, variables)
+	bodyLookup := func(variable string) (T, bool) {
+		for i := len(values) - 1; i >= 0; i-- {
+			if n.args[i].token == variable {
+				return values[i], true
+			}
+		}
+		return lookup(variable)
+	}
+	return lambda(func(args ...T) (T, error) {
+		if len(args) != variables {
+			return zero, errors.New(n.token + " binds " + strconv.Itoa(variables) + " variables, but got " + strconv.Itoa(len(args)))
+		}
+		copy(values, args)
+		return n.args[body].eval(bodyLookup, m)
+	}, params...)
+}
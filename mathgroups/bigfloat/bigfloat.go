@@ -0,0 +1,431 @@
+// Package bigfloat is an implementation of arbitrary-precision floating-point numbers (*big.Float)
+// with common operators and functions.
+package bigfloat
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Common functions and operations defined for the bigfloat group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Sin
+	Cos
+	Tan
+	Log
+	Exp
+	Sum
+	Prod
+)
+
+// Extra bits of precision used for intermediate results
+const guardBits = 64
+
+// The largest binary exponent of an argument to sin, cos and tan. Reducing an argument with exponent e
+// takes pi to e more bits than the working precision.
+const maxAngleExponent = 1 << 16
+
+// ErrOverflow is returned when a result is too large for a big.Float, which would otherwise be infinite.
+var ErrOverflow = errors.New("bigfloat overflow")
+
+// The precision and rounding mode of a group, along with constants at its working precision
+type context struct {
+	prec uint // Precision of results
+	work uint // Precision of intermediate results
+	mode big.RoundingMode
+	pi   *big.Float
+	ln2  *big.Float
+	e    *big.Float
+	eps  *big.Float // Series are summed until their terms are smaller than this
+}
+
+func newContext(prec uint, mode big.RoundingMode) *context {
+	c := &context{prec: prec, mode: mode}
+	c.setWork(prec + guardBits)
+	c.pi = c.machin()
+	// ln 2 = 2 atanh(1/3)
+	c.ln2 = c.new().Mul(c.new().SetInt64(2), c.arctan(c.new().Quo(c.one(), c.new().SetInt64(3)), false))
+	c.e, _ = c.exp(c.one())
+	return c
+}
+
+// Sets the working precision, and the size of the terms at which series stop
+func (c *context) setWork(work uint) {
+	c.work = work
+	c.eps = new(big.Float).SetMantExp(big.NewFloat(1), -int(work))
+}
+
+// Computes pi at the working precision with Machin's formula: pi = 16 atan(1/5) - 4 atan(1/239)
+func (c *context) machin() *big.Float {
+	return c.new().Sub(
+		c.new().Mul(c.new().SetInt64(16), c.arctan(c.new().Quo(c.one(), c.new().SetInt64(5)), true)),
+		c.new().Mul(c.new().SetInt64(4), c.arctan(c.new().Quo(c.one(), c.new().SetInt64(239)), true)),
+	)
+}
+
+// Returns a new value at the working precision
+func (c *context) new() *big.Float {
+	return new(big.Float).SetPrec(c.work)
+}
+
+func (c *context) one() *big.Float {
+	return c.new().SetInt64(1)
+}
+
+// Rounds a value to the precision and rounding mode of results
+func (c *context) round(x *big.Float) *big.Float {
+	return new(big.Float).SetPrec(c.prec).SetMode(c.mode).Set(x)
+}
+
+// Rounds the result of a function that may fail
+func (c *context) rounded(x *big.Float, err error) (*big.Float, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c.round(x), nil
+}
+
+func (c *context) negligible(term *big.Float) bool {
+	return new(big.Float).Abs(term).Cmp(c.eps) < 0
+}
+
+// Sums the series for atan(z) if alternating, or atanh(z) otherwise. Converges for |z| < 1.
+func (c *context) arctan(z *big.Float, alternating bool) *big.Float {
+	z2 := c.new().Mul(z, z)
+	power := c.new().Set(z)
+	sum := c.new().Set(z)
+	for k := int64(1); ; k++ {
+		power.Mul(power, z2)
+		term := c.new().Quo(power, c.new().SetInt64(2*k+1))
+		if c.negligible(term) {
+			return sum
+		}
+		if alternating && k%2 == 1 {
+			sum.Sub(sum, term)
+		} else {
+			sum.Add(sum, term)
+		}
+	}
+}
+
+func (c *context) exp(x *big.Float) (*big.Float, error) {
+	// Reduce to x = n ln 2 + r, so that exp(x) = 2^n exp(r) with |r| < ln 2
+	n, _ := c.new().Quo(x, c.ln2).Int64()
+	if n > math.MaxInt32/2 || n < math.MinInt32/2 {
+		return nil, ErrOverflow
+	}
+	r := c.new().Sub(x, c.new().Mul(c.new().SetInt64(n), c.ln2))
+	sum := c.one()
+	term := c.one()
+	for k := int64(1); ; k++ {
+		term.Mul(term, r)
+		term.Quo(term, c.new().SetInt64(k))
+		if c.negligible(term) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return sum.SetMantExp(sum, int(n)), nil
+}
+
+func (c *context) log(x *big.Float) (*big.Float, error) {
+	if x.Sign() <= 0 {
+		return nil, errors.New("logarithm of a non-positive number")
+	}
+	// x = m 2^e with 0.5 <= m < 1, and log(m) = 2 atanh((m - 1) / (m + 1))
+	m := c.new()
+	e := x.MantExp(m)
+	z := c.new().Quo(c.new().Sub(m, c.one()), c.new().Add(m, c.one()))
+	logM := c.new().Mul(c.new().SetInt64(2), c.arctan(z, false))
+	return logM.Add(logM, c.new().Mul(c.new().SetInt64(int64(e)), c.ln2)), nil
+}
+
+// Reduces x to the range (-2 pi, 2 pi) without changing its sine or cosine. Subtracting a multiple of 2 pi
+// cancels as many bits as the exponent of x, so pi is computed to that many more bits for large arguments.
+func (c *context) reduceAngle(x *big.Float) (*big.Float, error) {
+	exponent := x.MantExp(nil)
+	if exponent > maxAngleExponent {
+		return nil, errors.New("argument is too large to reduce")
+	}
+	wide := c
+	if exponent > 0 {
+		wide = &context{prec: c.prec, mode: c.mode}
+		wide.setWork(c.work + uint(exponent))
+		wide.pi = wide.machin()
+	}
+	twoPi := wide.new().Mul(wide.new().SetInt64(2), wide.pi)
+	k, _ := wide.new().Quo(x, twoPi).Int(nil)
+	return c.new().Sub(x, wide.new().Mul(wide.new().SetInt(k), twoPi)), nil
+}
+
+func (c *context) sin(x *big.Float) (*big.Float, error) {
+	r, err := c.reduceAngle(x)
+	if err != nil {
+		return nil, err
+	}
+	r2 := c.new().Mul(r, r)
+	sum := c.new().Set(r)
+	term := c.new().Set(r)
+	for k := int64(1); ; k++ {
+		term.Mul(term, r2)
+		term.Quo(term, c.new().SetInt64(-(2*k)*(2*k+1)))
+		if c.negligible(term) {
+			return sum, nil
+		}
+		sum.Add(sum, term)
+	}
+}
+
+func (c *context) cos(x *big.Float) (*big.Float, error) {
+	r, err := c.reduceAngle(x)
+	if err != nil {
+		return nil, err
+	}
+	r2 := c.new().Mul(r, r)
+	sum := c.one()
+	term := c.one()
+	for k := int64(1); ; k++ {
+		term.Mul(term, r2)
+		term.Quo(term, c.new().SetInt64(-(2*k-1)*(2*k)))
+		if c.negligible(term) {
+			return sum, nil
+		}
+		sum.Add(sum, term)
+	}
+}
+
+func (c *context) pow(x *big.Float, y *big.Float) (*big.Float, error) {
+	if y.IsInt() {
+		if n, accuracy := y.Int64(); accuracy == big.Exact && n < math.MaxInt32 && n > math.MinInt32 {
+			return c.intPow(x, n)
+		}
+	}
+	switch x.Sign() {
+	case 0:
+		if y.Sign() > 0 {
+			return c.new(), nil
+		}
+		return nil, types.ErrDivisionByZero
+	case -1:
+		return nil, errors.New("power of a negative number must have an integer exponent")
+	}
+	logX, err := c.log(x)
+	if err != nil {
+		return nil, err
+	}
+	return c.exp(c.new().Mul(y, logX))
+}
+
+// Raises x to an integer power by repeated squaring
+func (c *context) intPow(x *big.Float, n int64) (*big.Float, error) {
+	negative := n < 0
+	if negative {
+		n = -n
+	}
+	result := c.one()
+	square := c.new().Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, square)
+		}
+		square.Mul(square, square)
+	}
+	if negative {
+		if result.Sign() == 0 {
+			return nil, types.ErrDivisionByZero
+		}
+		return result.Quo(c.one(), result), nil
+	}
+	return result, nil
+}
+
+// Converts an integral value to an int, for the bounds of a series
+func toInt(x *big.Float) (int, bool) {
+	if !x.IsInt() {
+		return 0, false
+	}
+	n, _ := x.Int64()
+	return int(n), n >= -math.MaxInt32 && n <= math.MaxInt32
+}
+
+// Wraps an Apply function so that it rejects infinite arguments, and reports infinite results with ErrOverflow.
+// big.Float panics on operations such as Inf - Inf, so infinities are kept out of every computation.
+func finite(apply func(...*big.Float) (*big.Float, error)) func(...*big.Float) (*big.Float, error) {
+	return func(params ...*big.Float) (*big.Float, error) {
+		for _, param := range params {
+			if param.IsInf() {
+				return nil, errors.New("infinite values are not supported")
+			}
+		}
+		result, err := apply(params...)
+		if err == nil && result.IsInf() {
+			return nil, ErrOverflow
+		}
+		return result, err
+	}
+}
+
+func (c *context) tokenMap() map[types.Keyword]types.KeywordData[*big.Float] {
+	tokenMap := c.keywords()
+	for keyword, keywordData := range tokenMap {
+		keywordData.Apply = finite(keywordData.Apply)
+		tokenMap[keyword] = keywordData
+	}
+	return tokenMap
+}
+
+func (c *context) keywords() map[types.Keyword]types.KeywordData[*big.Float] {
+	return map[types.Keyword]types.KeywordData[*big.Float]{
+		Add: {Symbol: "+", TokenType: types.Operator,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.round(c.new().Add(params[0], params[1])), nil
+			}},
+		Subtract: {Symbol: "-", TokenType: types.Operator,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.round(c.new().Sub(params[0], params[1])), nil
+			}},
+		Multiply: {Symbol: "*", TokenType: types.Operator,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.round(c.new().Mul(params[0], params[1])), nil
+			}},
+		Divide: {Symbol: "/", TokenType: types.Operator,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				if params[1].Sign() == 0 {
+					return nil, types.ErrDivisionByZero
+				}
+				return c.round(c.new().Quo(params[0], params[1])), nil
+			}},
+		Power: {Symbol: "^", TokenType: types.Operator,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.rounded(c.pow(params[0], params[1]))
+			}},
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.round(c.new().Neg(params[0])), nil
+			}},
+		Sin: {Symbol: "sin", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.rounded(c.sin(params[0]))
+			}},
+		Cos: {Symbol: "cos", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.rounded(c.cos(params[0]))
+			}},
+		Tan: {Symbol: "tan", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				sin, err := c.sin(params[0])
+				if err != nil {
+					return nil, err
+				}
+				cos, _ := c.cos(params[0])
+				if cos.Sign() == 0 {
+					return nil, types.ErrDivisionByZero
+				}
+				return c.round(c.new().Quo(sin, cos)), nil
+			}},
+		Log: {Symbol: "log", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.rounded(c.log(params[0]))
+			}},
+		Exp: {Symbol: "exp", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Float) (*big.Float, error) {
+				return c.rounded(c.exp(params[0]))
+			}},
+		Sum: types.Series("sum", c.round(c.new()), func(x *big.Float, y *big.Float) (*big.Float, error) {
+			return c.round(c.new().Add(x, y)), nil
+		}, toInt),
+		Prod: types.Series("prod", c.round(c.one()), func(x *big.Float, y *big.Float) (*big.Float, error) {
+			return c.round(c.new().Mul(x, y)), nil
+		}, toInt),
+	}
+}
+
+var bigfloatStringToToken = map[string]types.Keyword{
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"neg":  Negate,
+	"sin":  Sin,
+	"cos":  Cos,
+	"tan":  Tan,
+	"log":  Log,
+	"exp":  Exp,
+	"sum":  Sum,
+	"prod": Prod,
+}
+
+var bigfloatOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses finite decimal literals, along with the constants "pi" and "e"
+func (c *context) getValue(s string) (*big.Float, bool) {
+	switch s {
+	case "pi":
+		return c.round(c.pi), true
+	case "e":
+		return c.round(c.e), true
+	}
+	value, _, err := big.ParseFloat(s, 10, c.prec, c.mode)
+	if err != nil || value.IsInf() {
+		return nil, false
+	}
+	return value, true
+}
+
+// Formats a value with as many significant digits as the precision allows
+func (c *context) formatValue(x *big.Float) string {
+	return x.Text('g', int(float64(c.prec)*math.Log10(2)))
+}
+
+// New constructs a bigfloat group whose results have the given precision (in bits) and rounding mode.
+// The group is named after both, such as "bigfloat(256,ToNearestEven)".
+func New(prec uint, mode big.RoundingMode) *types.MathGroup[*big.Float] {
+	if prec == 0 {
+		panic("Precision must be positive")
+	}
+	c := newContext(prec, mode)
+	name := "bigfloat(" + strconv.FormatUint(uint64(prec), 10) + "," + mode.String() + ")"
+	return types.NewMathGroup(c.tokenMap(), bigfloatStringToToken, bigfloatOperatorPrecedence, c.getValue, types.WithName[*big.Float](name), types.WithFormat(c.formatValue))
+}
+
+// NewDigits constructs a bigfloat group whose results have at least the given number of decimal digits of precision.
+func NewDigits(digits uint, mode big.RoundingMode) *types.MathGroup[*big.Float] {
+	return New(uint(math.Ceil(float64(digits)*math.Log2(10))), mode)
+}
+
+// NewInterval constructs a new bigfloat interval of finite values. Values in the interval have the precision of step.
+func NewInterval(start *big.Float, step *big.Float, end *big.Float) *types.Interval[*big.Float] {
+	if start.IsInf() || step.IsInf() || end.IsInf() || step.Sign() <= 0 || start.Cmp(end) > 0 {
+		panic("Invalid interval")
+	}
+	return &types.Interval[*big.Float]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur *big.Float) (*big.Float, bool) {
+			next := new(big.Float).SetPrec(step.Prec()).Add(cur, step)
+			if next.Cmp(end) > 0 {
+				return end, true
+			}
+			return next, false
+		},
+	}
+}
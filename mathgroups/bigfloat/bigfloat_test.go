@@ -0,0 +1,96 @@
+package bigfloat_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/bigfloat"
+	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
+)
+
+var group = bigfloat.NewDigits(60, big.ToNearestEven)
+
+// Compares the first 50 digits after the decimal point, without rounding
+func testMapValuesHelper(expression string, input string, expected string, t *testing.T) {
+	x, _ := group.GetValue(input)
+	runnableBigfloat := run.GetRunnableMathGroup(group)
+	f, err := runnableBigfloat.MapValues(expression, *bigfloat.NewInterval(x, big.NewFloat(1), x), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := f[0].Text('f', 55); !strings.HasPrefix(output, expected) {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("pi", "0", "3.14159265358979323846264338327950288419716939937510", t)
+	testMapValuesHelper("exp(x)", "1", "2.71828182845904523536028747135266249775724709369995", t)
+	testMapValuesHelper("log(x)", "10", "2.30258509299404568401799145468436420760110148862877", t)
+	testMapValuesHelper("sin(x)", "1", "0.84147098480789650665250232163029899962256306079837", t)
+	testMapValuesHelper("cos(x + 2 * pi)", "1", "0.54030230586813971740093660744297660373231042061792", t)
+	testMapValuesHelper("tan(x)", "1", "1.55740772465490223050697480745836017308725077238152", t)
+	testMapValuesHelper("x^0.5", "2", "1.41421356237309504880168872420969807856967187537694", t)
+	testMapValuesHelper("-x^-2 + 1 / 3", "2", "0.08333333333333333333333333333333333333333333333333", t)
+}
+
+func TestPrecision(t *testing.T) {
+	low := bigfloat.New(24, big.ToZero)
+	value, _ := low.GetValue("0.1")
+	if value.Prec() != 24 || value.Mode() != big.ToZero {
+		t.Error("Value has the wrong precision or rounding mode:", value.Prec(), value.Mode())
+	}
+	if output := low.FormatValue(value); output != "0.09999999" {
+		t.Error("FormatValue failed. Expected: 0.09999999 Got:", output)
+	}
+	if low.Name != "bigfloat(24,ToZero)" || bigfloat.New(24, big.ToNearestEven).Name == low.Name {
+		t.Error("Unexpected name", low.Name)
+	}
+}
+
+// Evaluates an expression without variables
+func evaluateConstant(group *types.MathGroup[*big.Float], expression string) (*big.Float, error) {
+	zero := big.NewFloat(0)
+	f, err := run.GetRunnableMathGroup(group).MapValues(expression, *bigfloat.NewInterval(zero, big.NewFloat(1), zero), "x")
+	if err != nil {
+		return nil, err
+	}
+	return f[0], nil
+}
+
+func TestLargeAngles(t *testing.T) {
+	low := bigfloat.New(128, big.ToNearestEven)
+	high := bigfloat.New(512, big.ToNearestEven)
+	for _, expression := range []string{"sin(1e30)", "cos(1e40)", "tan(-1e40)", "sin(2^1000)"} {
+		lowValue, err := evaluateConstant(low, expression)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		highValue, _ := evaluateConstant(high, expression)
+		if lowOutput, highOutput := lowValue.Text('e', 30), highValue.Text('e', 30); lowOutput != highOutput {
+			t.Error("Failed on expression", expression, "- Expected:", highOutput, "Got:", lowOutput)
+		}
+	}
+	if _, err := evaluateConstant(low, "sin(2^100000)"); err == nil {
+		t.Error("Expected an error on an argument too large to reduce")
+	}
+}
+
+func TestInfinity(t *testing.T) {
+	for _, s := range []string{"Inf", "-Inf", "+inf", "1e1000000000000"} {
+		if _, ok := group.GetValue(s); ok {
+			t.Error("Expected", s, "not to be a value")
+		}
+	}
+	for _, expression := range []string{"10^2000000000", "10^2000000000 - 10^2000000000", "0 * 10^2000000000", "exp(1e20)"} {
+		if _, err := evaluateConstant(group, expression); err != bigfloat.ErrOverflow {
+			t.Error("Expected an overflow on expression", expression, "Got:", err)
+		}
+	}
+}
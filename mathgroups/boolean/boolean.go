@@ -0,0 +1,94 @@
+// Package boolean is an implementation of boolean algebra with common logical operators.
+package boolean
+
+import (
+	"github.com/yasteen/go-parse/types"
+)
+
+// Common operations defined for the boolean group
+const (
+	And types.Keyword = iota
+	Or
+	Xor
+	Not
+	Implies
+	Iff
+)
+
+var booleanTokenMap = map[types.Keyword]types.KeywordData[bool]{
+	And: {Symbol: "&", TokenType: types.Operator,
+		Apply: func(params ...bool) (bool, error) {
+			return params[0] && params[1], nil
+		}},
+	Or: {Symbol: "|", TokenType: types.Operator,
+		Apply: func(params ...bool) (bool, error) {
+			return params[0] || params[1], nil
+		}},
+	Xor: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...bool) (bool, error) {
+			return params[0] != params[1], nil
+		}},
+	Not: {Symbol: "!", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...bool) (bool, error) {
+			return !params[0], nil
+		}},
+	Implies: {Symbol: "->", TokenType: types.Operator, Associativity: types.RightAssociative,
+		Apply: func(params ...bool) (bool, error) {
+			return !params[0] || params[1], nil
+		}},
+	Iff: {Symbol: "<->", TokenType: types.Operator,
+		Apply: func(params ...bool) (bool, error) {
+			return params[0] == params[1], nil
+		}},
+}
+
+var booleanStringToToken = map[string]types.Keyword{
+	"&":   And,
+	"|":   Or,
+	"^":   Xor,
+	"!":   Not,
+	"not": Not,
+	"->":  Implies,
+	"<->": Iff,
+}
+
+var booleanOperatorPrecedence = map[types.Keyword]int{
+	Iff:     1,
+	Implies: 2,
+	Or:      3,
+	Xor:     4,
+	And:     5,
+	Not:     6,
+}
+
+func getBoolean(s string) (bool, bool) {
+	switch s {
+	case "true", "1":
+		return true, true
+	case "false", "0":
+		return false, true
+	}
+	return false, false
+}
+
+func formatBoolean(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// Boolean represents boolean algebra (bool) and some defined operations
+var Boolean = types.NewMathGroup(booleanTokenMap, booleanStringToToken, booleanOperatorPrecedence, getBoolean, types.WithName[bool]("boolean"), types.WithFormat(formatBoolean))
+
+// NewInterval constructs the interval of both boolean values, false then true.
+func NewInterval() *types.Interval[bool] {
+	return &types.Interval[bool]{
+		Start: false,
+		Step:  true,
+		End:   true,
+		Next: func(cur bool) (bool, bool) {
+			return true, cur
+		},
+	}
+}
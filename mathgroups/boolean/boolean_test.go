@@ -0,0 +1,75 @@
+package boolean_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/boolean"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, expected []bool, t *testing.T) {
+	runnableBoolean := run.GetRunnableMathGroup(boolean.Boolean)
+	values, err := runnableBoolean.MapValues(expression, *boolean.NewInterval(), "p")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(values) != 2 || values[0] != expected[0] || values[1] != expected[1] {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", values)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("!p", []bool{true, false}, t)
+	testMapValuesHelper("p & true | false", []bool{false, true}, t)
+	testMapValuesHelper("p ^ 1", []bool{true, false}, t)
+	testMapValuesHelper("p -> 0", []bool{true, false}, t)
+	testMapValuesHelper("0 -> 0 -> p", []bool{true, true}, t)
+	testMapValuesHelper("not p <-> p", []bool{false, false}, t)
+	testMapValuesHelper("p | !p & false", []bool{false, true}, t)
+}
+
+func testTruthTableHelper(formula string, tautology bool, contradiction bool, satisfiable bool, t *testing.T) {
+	table, err := boolean.NewTruthTable(formula)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if table.IsTautology() != tautology || table.IsContradiction() != contradiction || table.IsSatisfiable() != satisfiable {
+		t.Error("Misclassified formula", formula, "\n"+table.String())
+	}
+}
+
+func TestTruthTable(t *testing.T) {
+	testTruthTableHelper("(p -> q) <-> (!q -> !p)", true, false, true, t)
+	testTruthTableHelper("p & !p", false, true, false, t)
+	testTruthTableHelper("p ^ q", false, false, true, t)
+	testTruthTableHelper("true", true, false, true, t)
+
+	table, err := boolean.NewTruthTable("a & b | c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Variables) != 3 || len(table.Rows) != 8 {
+		t.Fatal("Wrong truth table size:", table.Variables, len(table.Rows))
+	}
+	if row := table.Rows[6]; !row.Assignment[0] || !row.Assignment[1] || row.Assignment[2] || !row.Value {
+		t.Error("Wrong truth table row:", row)
+	}
+}
+
+func TestTruthTableSize(t *testing.T) {
+	for _, n := range []int{boolean.MaxTruthTableVariables + 1, 30, 64} {
+		variables := make([]string, n)
+		for i := range variables {
+			variables[i] = "p" + strconv.Itoa(i)
+		}
+		if _, err := boolean.NewTruthTable(strings.Join(variables, " | ")); err == nil {
+			t.Error("Expected an error on a formula with", n, "variables")
+		}
+	}
+}
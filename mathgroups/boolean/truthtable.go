@@ -0,0 +1,109 @@
+package boolean
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/evaluate"
+	"github.com/yasteen/go-parse/parsexp"
+)
+
+// MaxTruthTableVariables is the most variables a truth table can have. A table has a row for each of the
+// 2^n assignments of its n variables, so it grows past a million rows above this.
+const MaxTruthTableVariables = 20
+
+// TruthRow is one assignment of a formula's variables, and the formula's value under it.
+type TruthRow struct {
+	Assignment []bool // In the order of TruthTable.Variables
+	Value      bool
+}
+
+// TruthTable holds the value of a formula under every assignment of its variables.
+type TruthTable struct {
+	Variables []string
+	Rows      []TruthRow
+}
+
+// NewTruthTable evaluates the formula under every assignment of its variables. Variables are
+// in order of first appearance. Rows are in counting order, with false before true.
+// Formulas with more than MaxTruthTableVariables variables are rejected.
+func NewTruthTable(formula string) (*TruthTable, error) {
+	variables, err := parsexp.Variables(formula, Boolean)
+	if err != nil {
+		return nil, err
+	}
+	if len(variables) > MaxTruthTableVariables {
+		return nil, errors.New("formula has " + strconv.Itoa(len(variables)) + " variables, but a truth table can have at most " + strconv.Itoa(MaxTruthTableVariables))
+	}
+	parsed, err := parsexp.ParseVars(formula, variables, Boolean)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &TruthTable{Variables: variables, Rows: make([]TruthRow, 0, 1<<len(variables))}
+	assignment := make(map[string]bool, len(variables))
+	for n := 0; n < 1<<len(variables); n++ {
+		row := TruthRow{Assignment: make([]bool, len(variables))}
+		for i, v := range variables {
+			// The first variable is the most significant bit
+			row.Assignment[i] = n&(1<<(len(variables)-1-i)) != 0
+			assignment[v] = row.Assignment[i]
+		}
+		if row.Value, err = evaluate.OnceVars(parsed, assignment, Boolean); err != nil {
+			return nil, err
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// IsTautology returns true if the formula is true under every assignment.
+func (t *TruthTable) IsTautology() bool {
+	for _, row := range t.Rows {
+		if !row.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSatisfiable returns true if the formula is true under some assignment.
+func (t *TruthTable) IsSatisfiable() bool {
+	for _, row := range t.Rows {
+		if row.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsContradiction returns true if the formula is false under every assignment.
+func (t *TruthTable) IsContradiction() bool {
+	return !t.IsSatisfiable()
+}
+
+// String formats the table with a column for each variable and one for the formula, using 1 and 0.
+func (t *TruthTable) String() string {
+	var b strings.Builder
+	widths := make([]int, len(t.Variables))
+	for i, v := range t.Variables {
+		widths[i] = len(v)
+		b.WriteString(v + " ")
+	}
+	b.WriteString("| result\n")
+	for _, row := range t.Rows {
+		for i, value := range row.Assignment {
+			b.WriteString(bit(value) + strings.Repeat(" ", widths[i]))
+		}
+		b.WriteString("| " + bit(row.Value) + "\n")
+	}
+	return b.String()
+}
+
+func bit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
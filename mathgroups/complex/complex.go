@@ -2,10 +2,13 @@
 package complex
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"strings"
 
+	"github.com/yasteen/go-parse/evaluate"
+	"github.com/yasteen/go-parse/parsexp"
 	"github.com/yasteen/go-parse/types"
 )
 
@@ -15,7 +18,7 @@ type Number struct {
 	Im float64
 }
 
-// Common functions and operations defined for the real group
+// Common functions and operations defined for the complex group
 const (
 	Add types.Keyword = iota
 	Subtract
@@ -27,20 +30,29 @@ const (
 	Tan
 	Log
 	Exp
+	Sqrt
+	Abs
+	Arg
+	Conj
+	Re
+	Im
+	Sinh
+	Cosh
+	Tanh
+	Asin
+	Acos
+	Atan
+	Negate
+	Cis
+	Angle
+	Degrees
+	Sum
+	Prod
 )
 
-// Helper function to convert from Cartesian to Polar form
+// Helper function to convert from Cartesian to Polar form. The argument is in (-pi, pi].
 func cartesianToPolar(re float64, im float64) (mod float64, arg float64) {
-	if re == 0 && im == 0 {
-		panic("Arg is undefined.")
-	}
-	mod = re*re + im*im
-	if re > 0 || im != 0 {
-		arg = 2 * math.Atan(im/math.Sqrt(mod)+re)
-	} else {
-		arg = math.Pi
-	}
-	return mod, arg
+	return math.Hypot(re, im), math.Atan2(im, re)
 }
 
 func opAdd(params ...Number) Number {
@@ -65,27 +77,30 @@ func opMultiply(params ...Number) Number {
 		Im: xr*yi + xi*yr,
 	}
 }
-func opDivide(params ...Number) Number {
+func opDivide(params ...Number) (Number, error) {
 	xr := params[0].Re
 	xi := params[0].Im
 	yr := params[1].Re
 	yi := params[1].Im
 	if yr == 0 && yi == 0 {
-		panic("Attempted division by 0")
+		return Number{}, types.ErrDivisionByZero
 	}
 	return Number{
 		Re: (xr*yr + xi*yi) / (yr*yr + yi*yi),
 		Im: (xi*yr - xr*yi) / (yr*yr + yi*yi),
-	}
+	}, nil
 }
-func fnLog(params ...Number) Number {
+func fnLog(params ...Number) (Number, error) {
 	re := params[0].Re
 	im := params[0].Im
+	if re == 0 && im == 0 {
+		return Number{}, errors.New("logarithm of 0 is undefined")
+	}
 	mod, arg := cartesianToPolar(re, im)
 	return Number{
 		Re: math.Log(mod),
 		Im: arg,
-	}
+	}, nil
 }
 func fnExp(params ...Number) Number {
 	re := params[0].Re
@@ -98,57 +113,239 @@ func fnExp(params ...Number) Number {
 }
 func fnSin(params ...Number) Number {
 	re := params[0].Re
-	im := params[1].Im
-	first := fnExp(Number{-im, re})  // e^(iz)
-	second := fnExp(Number{im, -re}) // e^(-iz)
+	im := params[0].Im
 	return Number{
-		Re: (first.Im - second.Im) / 2,
-		Im: (second.Re - first.Re) / 2,
+		Re: math.Sin(re) * math.Cosh(im),
+		Im: math.Cos(re) * math.Sinh(im),
 	}
 }
 func fnCos(params ...Number) Number {
 	re := params[0].Re
-	im := params[1].Im
-	first := fnExp(Number{-im, re})  // e^(iz)
-	second := fnExp(Number{im, -re}) // e^(-iz)
+	im := params[0].Im
+	return Number{
+		Re: math.Cos(re) * math.Cosh(im),
+		Im: -math.Sin(re) * math.Sinh(im),
+	}
+}
+func fnSinh(params ...Number) Number {
+	re := params[0].Re
+	im := params[0].Im
+	return Number{
+		Re: math.Sinh(re) * math.Cos(im),
+		Im: math.Cosh(re) * math.Sin(im),
+	}
+}
+func fnCosh(params ...Number) Number {
+	re := params[0].Re
+	im := params[0].Im
 	return Number{
-		Re: (first.Re - second.Re) / 2,
-		Im: (first.Im - second.Im) / 2,
+		Re: math.Cosh(re) * math.Cos(im),
+		Im: math.Sinh(re) * math.Sin(im),
+	}
+}
+
+// The principal square root, with a non-negative real part
+func fnSqrt(params ...Number) Number {
+	re := params[0].Re
+	im := params[0].Im
+	if re == 0 && im == 0 {
+		return Number{0, im}
+	}
+	mod := math.Hypot(re, im)
+	// Computes the larger part directly, and the smaller from it, to avoid cancellation
+	if re > 0 {
+		t := math.Sqrt((mod + re) / 2)
+		return Number{t, im / (2 * t)}
+	}
+	t := math.Sqrt((mod - re) / 2)
+	return Number{math.Abs(im) / (2 * t), math.Copysign(t, im)}
+}
+
+// Principal inverse sine, -i log(iz + sqrt(1 - z^2))
+func fnAsin(params ...Number) (Number, error) {
+	z := params[0]
+	iz := Number{-z.Im, z.Re}
+	root := fnSqrt(opSubtract(Number{1, 0}, opMultiply(z, z)))
+	log, err := fnLog(opAdd(iz, root))
+	if err != nil {
+		return Number{}, err
+	}
+	return Number{log.Im, -log.Re}, nil
+}
+
+// Principal inverse cosine, pi/2 - asin(z)
+func fnAcos(params ...Number) (Number, error) {
+	asin, err := fnAsin(params[0])
+	if err != nil {
+		return Number{}, err
+	}
+	return opSubtract(Number{math.Pi / 2, 0}, asin), nil
+}
+
+// Principal inverse tangent, i/2 log((i + z) / (i - z)), which is undefined at i and -i
+func fnAtan(params ...Number) (Number, error) {
+	z := params[0]
+	quotient, err := opDivide(opAdd(Number{0, 1}, z), opSubtract(Number{0, 1}, z))
+	if err != nil {
+		return Number{}, err
+	}
+	log, err := fnLog(quotient)
+	if err != nil {
+		return Number{}, err
+	}
+	return Number{-log.Im / 2, log.Re / 2}, nil
+}
+
+// The principal value of x^y, exp(y log x)
+func opPower(params ...Number) (Number, error) {
+	x, y := params[0], params[1]
+	if x.Re == 0 && x.Im == 0 {
+		switch {
+		case y.Re == 0 && y.Im == 0:
+			return Number{1, 0}, nil
+		case y.Re > 0:
+			return Number{0, 0}, nil
+		}
+		return Number{}, types.ErrDivisionByZero
+	}
+	log, err := fnLog(x)
+	if err != nil {
+		return Number{}, err
+	}
+	return fnExp(opMultiply(y, log)), nil
+}
+
+// cis(theta) = cos(theta) + i sin(theta)
+func fnCis(params ...Number) Number {
+	return fnExp(Number{-params[0].Im, params[0].Re})
+}
+
+// Returns a function of a complex number with a real result
+func realFunction(f func(Number) float64) func(...Number) Number {
+	return func(params ...Number) Number {
+		return Number{f(params[0]), 0}
 	}
 }
 
-var complexTokenMap = map[types.Keyword]types.KeywordData[Number]{
-	Add:      {Symbol: "+", TokenType: types.Operator, Apply: opAdd},
-	Subtract: {Symbol: "-", TokenType: types.Operator, Apply: opSubtract},
-	Multiply: {Symbol: "*", TokenType: types.Operator, Apply: opMultiply},
-	Divide:   {Symbol: "/", TokenType: types.Operator, Apply: opDivide},
-	Power: {Symbol: "^", TokenType: types.Operator,
-		Apply: func(params ...Number) Number {
-			return fnExp(opMultiply(params[1], fnLog(params[0])))
+// Converts a real integral value to an int, for the bounds of a series
+func toInt(z Number) (int, bool) {
+	return int(z.Re), z.Im == 0 && z.Re == math.Trunc(z.Re) && math.Abs(z.Re) <= math.MaxInt32
+}
+
+// Multiplies a complex number by a real number
+func scale(z Number, x float64) Number {
+	return Number{z.Re * x, z.Im * x}
+}
+
+// Returns the keywords of a complex group whose angles are in units of the given size, in radians
+func complexTokenMap(unit float64) map[types.Keyword]types.KeywordData[Number] {
+	// Adapts a trigonometric function to read angles in the unit
+	fromAngle := func(f func(...Number) Number) func(...Number) Number {
+		return func(params ...Number) Number { return f(scale(params[0], unit)) }
+	}
+	// Adapts an inverse trigonometric function to return angles in the unit
+	toAngle := func(f func(...Number) (Number, error)) func(...Number) (Number, error) {
+		return func(params ...Number) (Number, error) {
+			z, err := f(params...)
+			return scale(z, 1/unit), err
+		}
+	}
+	return map[types.Keyword]types.KeywordData[Number]{
+		Add:      {Symbol: "+", TokenType: types.Operator, Apply: types.Infallible(opAdd)},
+		Subtract: {Symbol: "-", TokenType: types.Operator, Apply: types.Infallible(opSubtract)},
+		Multiply: {Symbol: "*", TokenType: types.Operator, Apply: types.Infallible(opMultiply)},
+		Divide:   {Symbol: "/", TokenType: types.Operator, Apply: opDivide},
+		Power:    {Symbol: "^", TokenType: types.Operator, Apply: opPower},
+		Sin:      {Symbol: "sin", TokenType: types.SingleFunction, Apply: types.Infallible(fromAngle(fnSin))},
+		Cos:      {Symbol: "cos", TokenType: types.SingleFunction, Apply: types.Infallible(fromAngle(fnCos))},
+		Tan: {Symbol: "tan", TokenType: types.SingleFunction,
+			Apply: func(params ...Number) (Number, error) {
+				return opDivide(fnSin(scale(params[0], unit)), fnCos(scale(params[0], unit)))
+			},
+		},
+		Log:  {Symbol: "log", TokenType: types.SingleFunction, Apply: fnLog},
+		Exp:  {Symbol: "exp", TokenType: types.SingleFunction, Apply: types.Infallible(fnExp)},
+		Sqrt: {Symbol: "sqrt", TokenType: types.SingleFunction, Apply: types.Infallible(fnSqrt)},
+		Abs: {Symbol: "abs", TokenType: types.SingleFunction,
+			Apply: types.Infallible(realFunction(func(z Number) float64 { return math.Hypot(z.Re, z.Im) }))},
+		Arg: {Symbol: "arg", TokenType: types.SingleFunction,
+			Apply: types.Infallible(realFunction(func(z Number) float64 { return math.Atan2(z.Im, z.Re) / unit }))},
+		Conj: {Symbol: "conj", TokenType: types.SingleFunction,
+			Apply: func(params ...Number) (Number, error) {
+				return Number{params[0].Re, -params[0].Im}, nil
+			},
 		},
-	},
-	Sin: {Symbol: "sin", TokenType: types.SingleFunction, Apply: fnSin},
-	Cos: {Symbol: "cos", TokenType: types.SingleFunction, Apply: fnCos},
-	Tan: {Symbol: "tan", TokenType: types.SingleFunction,
-		Apply: func(params ...Number) Number {
-			return opDivide(fnSin(params[0]), fnCos(params[0]))
+		Re: {Symbol: "re", TokenType: types.SingleFunction,
+			Apply: types.Infallible(realFunction(func(z Number) float64 { return z.Re }))},
+		Im: {Symbol: "im", TokenType: types.SingleFunction,
+			Apply: types.Infallible(realFunction(func(z Number) float64 { return z.Im }))},
+		Sinh: {Symbol: "sinh", TokenType: types.SingleFunction, Apply: types.Infallible(fnSinh)},
+		Cosh: {Symbol: "cosh", TokenType: types.SingleFunction, Apply: types.Infallible(fnCosh)},
+		Tanh: {Symbol: "tanh", TokenType: types.SingleFunction,
+			Apply: func(params ...Number) (Number, error) {
+				return opDivide(fnSinh(params[0]), fnCosh(params[0]))
+			},
 		},
-	},
-	Log: {Symbol: "log", TokenType: types.SingleFunction, Apply: fnLog},
-	Exp: {Symbol: "exp", TokenType: types.SingleFunction, Apply: fnExp},
+		Asin: {Symbol: "asin", TokenType: types.SingleFunction, Apply: toAngle(fnAsin)},
+		Acos: {Symbol: "acos", TokenType: types.SingleFunction, Apply: toAngle(fnAcos)},
+		Atan: {Symbol: "atan", TokenType: types.SingleFunction, Apply: toAngle(fnAtan)},
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			// 0 - x keeps zero parts +0, so that sqrt(-4) is 2i rather than -2i
+			Apply: func(params ...Number) (Number, error) {
+				return Number{0 - params[0].Re, 0 - params[0].Im}, nil
+			},
+		},
+		Cis: {Symbol: "cis", TokenType: types.SingleFunction, Apply: types.Infallible(fromAngle(fnCis))},
+		// r∠θ is the number with modulus r and argument θ, r cis(θ)
+		Angle: {Symbol: "∠", TokenType: types.Operator,
+			Apply: func(params ...Number) (Number, error) {
+				return opMultiply(params[0], fnCis(scale(params[1], unit))), nil
+			},
+		},
+		// θ° is an angle of θ degrees, in the unit of the group
+		Degrees: {Symbol: "°", TokenType: types.Operator, Fixity: types.Postfix,
+			Apply: func(params ...Number) (Number, error) {
+				return scale(params[0], types.Degrees.Size()/unit), nil
+			},
+		},
+		Sum: types.Series("sum", Number{0, 0}, func(z Number, w Number) (Number, error) {
+			return opAdd(z, w), nil
+		}, toInt),
+		Prod: types.Series("prod", Number{1, 0}, func(z Number, w Number) (Number, error) {
+			return opMultiply(z, w), nil
+		}, toInt),
+	}
 }
 
 var complexStringToToken = map[string]types.Keyword{
-	"+":   Add,
-	"-":   Subtract,
-	"*":   Multiply,
-	"/":   Divide,
-	"^":   Power,
-	"sin": Sin,
-	"cos": Cos,
-	"tan": Tan,
-	"log": Log,
-	"exp": Exp,
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"sin":  Sin,
+	"cos":  Cos,
+	"tan":  Tan,
+	"log":  Log,
+	"exp":  Exp,
+	"sqrt": Sqrt,
+	"abs":  Abs,
+	"arg":  Arg,
+	"conj": Conj,
+	"re":   Re,
+	"im":   Im,
+	"sinh": Sinh,
+	"cosh": Cosh,
+	"tanh": Tanh,
+	"asin": Asin,
+	"acos": Acos,
+	"atan": Atan,
+	"neg":  Negate,
+	"cis":  Cis,
+	"∠":    Angle,
+	"°":    Degrees,
+	"sum":  Sum,
+	"prod": Prod,
 }
 
 var complexOperatorPrecedence = map[types.Keyword]int{
@@ -156,7 +353,10 @@ var complexOperatorPrecedence = map[types.Keyword]int{
 	Subtract: 1,
 	Multiply: 2,
 	Divide:   2,
+	Negate:   2,
 	Power:    3,
+	Angle:    4,
+	Degrees:  5,
 }
 
 func getComplex(s string) (Number, bool) {
@@ -185,8 +385,78 @@ func getComplex(s string) (Number, bool) {
 	return Number{0, 0}, false
 }
 
+// Form is the form a complex number is formatted in.
+type Form int
+
+// The possible forms
+const (
+	Cartesian    Form = iota // Like 3 - 2i
+	Polar                    // Like 5∠0.9273, with the argument in radians
+	PolarDegrees             // Like 5∠53.13°, with the argument in degrees
+)
+
+// Format formats a complex number in the given form, with the given number of digits after the decimal point.
+// If precision is -1, each part is formatted with the fewest digits that represent it exactly.
+// A polar number is formatted with its principal argument, in (-pi, pi].
+func Format(n Number, form Form, precision int) string {
+	format := func(x float64) string {
+		if precision < 0 {
+			return strconv.FormatFloat(x, 'g', -1, 64)
+		}
+		return strconv.FormatFloat(x, 'f', precision, 64)
+	}
+	if form == Polar || form == PolarDegrees {
+		mod, arg := cartesianToPolar(n.Re, n.Im)
+		if form == PolarDegrees {
+			return format(mod) + "∠" + format(arg*180/math.Pi) + "°"
+		}
+		return format(mod) + "∠" + format(arg)
+	}
+
+	re := format(n.Re)
+	im := format(math.Abs(n.Im))
+	if im == "1" {
+		im = ""
+	}
+	switch {
+	case n.Im == 0:
+		return re
+	case n.Re == 0 && n.Im < 0:
+		return "-" + im + "i"
+	case n.Re == 0:
+		return im + "i"
+	case n.Im < 0:
+		return re + " - " + im + "i"
+	default:
+		return re + " + " + im + "i"
+	}
+}
+
+// Formats a complex number like "3 - 2i"
+func formatComplex(n Number) string {
+	return Format(n, Cartesian, -1)
+}
+
 // Complex represents the complex number system (float64, float64) and some defined operations/functions
-var Complex = types.NewMathGroup(complexTokenMap, complexStringToToken, complexOperatorPrecedence, getComplex)
+var Complex = New(types.Radians)
+
+// New constructs a complex group whose trigonometric functions, cis and ∠ read angles in the given mode,
+// and whose inverse trigonometric functions and arg return them in it. In any mode, θ° is an angle of θ degrees.
+// The group is named after its mode, such as "complex(degrees)".
+func New(mode types.AngleMode) *types.MathGroup[Number] {
+	return types.NewMathGroup(complexTokenMap(mode.Size()), complexStringToToken, complexOperatorPrecedence, getComplex,
+		types.WithName[Number]("complex("+mode.String()+")"), types.WithFormat(formatComplex), types.WithAngleMode[Number](mode))
+}
+
+// Parse parses a complex number written in Cartesian form, such as "3 + 4i", or in polar form,
+// such as "2∠45°" or "2*cis(0.5)". It may be any expression in the complex group without variables.
+func Parse(s string) (Number, error) {
+	parsed, err := parsexp.ParseVars(s, nil, Complex)
+	if err != nil {
+		return Number{}, err
+	}
+	return evaluate.OnceVars(parsed, map[string]Number{}, Complex)
+}
 
 // NewComplexInterval constructs a new complex interval (top right to bottom left corner in Cartesian form)
 func NewComplexInterval(start Number, step Number, end Number) *types.Interval[Number] {
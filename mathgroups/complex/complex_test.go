@@ -2,10 +2,13 @@ package complex_test
 
 import (
 	"math"
+	"math/cmplx"
 	"testing"
 
 	"github.com/yasteen/go-parse/mathgroups/complex"
+	"github.com/yasteen/go-parse/parsexp"
 	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
 )
 
 var MIN_THRESHOLD = math.Pow10(-10)
@@ -39,3 +42,136 @@ func TestMapValues(t *testing.T) {
 	testMapValuesHelper("(3i + 2_3) * x", complex.Number{3, 2}, complex.Number{-6, 22}, t)
 	testMapValuesHelper("exp(i * x)", complex.Number{math.Pi, 0}, complex.Number{-1, 0}, t)
 }
+
+func TestFormatValue(t *testing.T) {
+	output, err := parsexp.ToInfix(parsexp.ParsedExpression{"x", "3_-2", "*", "i", "+"}, complex.Complex, parsexp.MinimalParens)
+	if err != nil {
+		t.Error(err)
+	}
+	if expected := "x * (3 - 2i) + i"; output != expected {
+		t.Errorf("ToInfix failed. Expected '%s', got '%s'", expected, output)
+	}
+}
+
+func TestFunctionsAgainstCmplx(t *testing.T) {
+	functions := map[string]func(complex128) complex128{
+		"sin(x)":  cmplx.Sin,
+		"cos(x)":  cmplx.Cos,
+		"tan(x)":  cmplx.Tan,
+		"log(x)":  cmplx.Log,
+		"exp(x)":  cmplx.Exp,
+		"sqrt(x)": cmplx.Sqrt,
+		"abs(x)":  func(z complex128) complex128 { return cmplx.Rect(cmplx.Abs(z), 0) },
+		"arg(x)":  func(z complex128) complex128 { return cmplx.Rect(cmplx.Phase(z), 0) },
+		"conj(x)": cmplx.Conj,
+		"re(x)":   func(z complex128) complex128 { return cmplx.Rect(real(z), 0) },
+		"im(x)":   func(z complex128) complex128 { return cmplx.Rect(imag(z), 0) },
+		"sinh(x)": cmplx.Sinh,
+		"cosh(x)": cmplx.Cosh,
+		"tanh(x)": cmplx.Tanh,
+		"asin(x)": cmplx.Asin,
+		"acos(x)": cmplx.Acos,
+		"atan(x)": cmplx.Atan,
+		"x^2.5":   func(z complex128) complex128 { return cmplx.Pow(z, 2.5) },
+		"x^1_1":   func(z complex128) complex128 { return cmplx.Pow(z, 1+1i) },
+	}
+	// Points in every quadrant and on the axes, away from branch cuts
+	points := []complex128{0.5, -0.5, 0.5i, -0.5i, 1 + 2i, -1.5 + 0.5i, -2 - 3i, 0.3 - 0.7i, 3 + 0.1i}
+	for expression, expected := range functions {
+		for _, z := range points {
+			want := expected(z)
+			testMapValuesHelper(expression, complex.Number{Re: real(z), Im: imag(z)}, complex.Number{Re: real(want), Im: imag(want)}, t)
+		}
+	}
+}
+
+func TestPowerOfZero(t *testing.T) {
+	testMapValuesHelper("x^2", complex.Number{0, 0}, complex.Number{0, 0}, t)
+	testMapValuesHelper("x^0", complex.Number{0, 0}, complex.Number{1, 0}, t)
+	testMapValuesHelper("sqrt(x)", complex.Number{-4, 0}, complex.Number{0, 2}, t)
+}
+
+func TestNegativeLiterals(t *testing.T) {
+	testMapValuesHelper("sqrt(-4)", complex.Number{0, 0}, complex.Number{0, 2}, t)
+	testMapValuesHelper("log(-1)", complex.Number{0, 0}, complex.Number{0, math.Pi}, t)
+	testMapValuesHelper("sqrt(-x)", complex.Number{4, 0}, complex.Number{0, 2}, t)
+	testMapValuesHelper("-(-9)^0.5", complex.Number{0, 0}, complex.Number{0, -3}, t)
+}
+
+func TestNotation(t *testing.T) {
+	testMapValuesHelper("3+4i", complex.Number{0, 0}, complex.Number{3, 4}, t)
+	testMapValuesHelper("-3-4i", complex.Number{0, 0}, complex.Number{-3, -4}, t)
+	testMapValuesHelper("x * (3-i)", complex.Number{0, 1}, complex.Number{1, 3}, t)
+	testMapValuesHelper("2∠90°", complex.Number{0, 0}, complex.Number{0, 2}, t)
+	testMapValuesHelper("1 + 2∠180° * x", complex.Number{2, 0}, complex.Number{-3, 0}, t)
+	testMapValuesHelper("x∠0.5 - 2*cis(0.5)", complex.Number{2, 0}, complex.Number{0, 0}, t)
+}
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]complex.Number{
+		"3 + 4i":     {3, 4},
+		"-2.5i":      {0, -2.5},
+		"2∠45°":      {math.Sqrt2, math.Sqrt2},
+		"2*cis(0.5)": {2 * math.Cos(0.5), 2 * math.Sin(0.5)},
+		"1_2":        {1, 2},
+	} {
+		n, err := complex.Parse(s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !equalEnough(n.Re, expected.Re) || !equalEnough(n.Im, expected.Im) {
+			t.Error("Parse failed on", s, "- Expected:", expected, "Got:", n)
+		}
+	}
+	if _, err := complex.Parse("3 + x"); err == nil {
+		t.Error("Expected an error when parsing a variable")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, test := range []struct {
+		n         complex.Number
+		form      complex.Form
+		precision int
+		expected  string
+	}{
+		{complex.Number{3, -4}, complex.Cartesian, -1, "3 - 4i"},
+		{complex.Number{3, -4}, complex.Cartesian, 2, "3.00 - 4.00i"},
+		{complex.Number{0, 1}, complex.Cartesian, -1, "i"},
+		{complex.Number{3, 4}, complex.Polar, 4, "5.0000∠0.9273"},
+		{complex.Number{3, 4}, complex.PolarDegrees, 2, "5.00∠53.13°"},
+		{complex.Number{-2, 0}, complex.PolarDegrees, -1, "2∠180°"},
+	} {
+		if output := complex.Format(test.n, test.form, test.precision); output != test.expected {
+			t.Errorf("Format failed. Expected '%s', got '%s'", test.expected, output)
+		}
+	}
+}
+
+func TestAngleModes(t *testing.T) {
+	degrees := complex.New(types.Degrees)
+	if degrees.AngleMode() != types.Degrees || complex.Complex.AngleMode() != types.Radians {
+		t.Error("Unexpected angle modes", degrees.AngleMode(), complex.Complex.AngleMode())
+	}
+	if degrees.Name != "complex(degrees)" || complex.Complex.Name != "complex(radians)" {
+		t.Error("Unexpected names", degrees.Name, complex.Complex.Name)
+	}
+	runnable := run.GetRunnableMathGroup(degrees)
+	for expression, expected := range map[string]complex.Number{
+		"sin(x)":             {0.5, 0},
+		"2∠x + cis(90)":      {math.Sqrt(3), 2},
+		"2∠x° - 2∠30":        {0, 0},
+		"arg(1_1) + asin(1)": {135, 0},
+		"acos(cos(x))":       {30, 0},
+	} {
+		c, err := runnable.MapValues(expression, *complex.NewComplexInterval(complex.Number{30, 0}, complex.Number{1, 0}, complex.Number{30, 0}), "x")
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !equalEnough(c[0].Re, expected.Re) || !equalEnough(c[0].Im, expected.Im) {
+			t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", c[0])
+		}
+	}
+}
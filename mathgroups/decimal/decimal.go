@@ -0,0 +1,295 @@
+// Package decimal is an implementation of fixed-point decimal numbers with explicit rounding, such as for money.
+package decimal
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Decimal is a fixed-point number, stored as an integer number of units of 10^-Scale.
+type Decimal struct {
+	Units int64
+	Scale int
+}
+
+// RoundingMode determines how results are rounded to the scale of a group.
+type RoundingMode int
+
+// The possible rounding modes
+const (
+	HalfEven RoundingMode = iota // To the nearest unit, and ties to even (banker's rounding)
+	HalfUp                       // To the nearest unit, and ties away from zero
+	Truncate                     // Toward zero
+)
+
+// String returns the name of the rounding mode, such as "HalfEven".
+func (mode RoundingMode) String() string {
+	switch mode {
+	case HalfUp:
+		return "HalfUp"
+	case Truncate:
+		return "Truncate"
+	}
+	return "HalfEven"
+}
+
+// Common functions and operations defined for the decimal group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Sum
+	Prod
+)
+
+// ErrOverflow is returned when a result does not fit in a Decimal.
+var ErrOverflow = errors.New("decimal overflow")
+
+// The largest supported scale, so that a single unit fits in an int64
+const maxScale = 18
+
+// String formats a decimal with exactly Scale digits after the decimal point, such as "-12.30".
+func (d Decimal) String() string {
+	digits := strconv.FormatUint(absUnits(d.Units), 10)
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+	sign := ""
+	if d.Units < 0 {
+		sign = "-"
+	}
+	if d.Scale == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+}
+
+// Rat returns the exact value of a decimal.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(d.Units), pow10(d.Scale))
+}
+
+func absUnits(units int64) uint64 {
+	if units < 0 {
+		return uint64(-(units + 1)) + 1
+	}
+	return uint64(units)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// The scale and rounding mode of a group
+type context struct {
+	scale int
+	mode  RoundingMode
+	unit  *big.Int // 10^scale
+}
+
+// Rounds an exact value to the scale of the group
+func (c context) round(r *big.Rat) (Decimal, error) {
+	num := new(big.Int).Mul(r.Num(), c.unit)
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && c.mode != Truncate {
+		// Compare twice the remainder with the denominator to find which unit is nearest
+		cmp := new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(r.Denom())
+		if cmp > 0 || (cmp == 0 && (c.mode == HalfUp || quo.Bit(0) == 1)) {
+			quo.Add(quo, big.NewInt(int64(rem.Sign())))
+		}
+	}
+	if !quo.IsInt64() {
+		return Decimal{}, ErrOverflow
+	}
+	return Decimal{Units: quo.Int64(), Scale: c.scale}, nil
+}
+
+// Returns true if every value at least as small as r rounds to zero, in any rounding mode
+func (c context) negligible(r *big.Rat) bool {
+	limit := new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Mul(c.unit, big.NewInt(10)))
+	return new(big.Rat).Abs(r).Cmp(limit) < 0
+}
+
+// Returns true if |r| is too large to be rounded into a Decimal
+func (c context) overflows(r *big.Rat) bool {
+	limit := new(big.Rat).SetFrac(new(big.Int).SetUint64(math.MaxInt64+1), c.unit)
+	return new(big.Rat).Abs(r).Cmp(limit) > 0
+}
+
+// Raises x to an integer power exactly by repeated squaring, and rounds the result
+func (c context) pow(x Decimal, exponent Decimal) (Decimal, error) {
+	n, ok := integer(exponent)
+	if !ok {
+		return Decimal{}, errors.New("exponent must be an integer")
+	}
+	base := x.Rat()
+	if n < 0 {
+		if base.Sign() == 0 {
+			return Decimal{}, types.ErrDivisionByZero
+		}
+		base.Inv(base)
+		n = -n
+	}
+	result := big.NewRat(1, 1)
+	for n > 0 {
+		if n&1 == 1 {
+			result.Mul(result, base)
+			if c.overflows(result) {
+				return Decimal{}, ErrOverflow
+			}
+		}
+		n >>= 1
+		if n > 0 {
+			base.Mul(base, base)
+			// Every remaining bit multiplies the result by base at least once more
+			if c.negligible(base) {
+				return Decimal{Scale: c.scale}, nil
+			}
+			if c.overflows(base) {
+				return Decimal{}, ErrOverflow
+			}
+		}
+	}
+	return c.round(result)
+}
+
+// Converts an integral value of any scale to an int64, and reports whether it is an integer
+func integer(x Decimal) (int64, bool) {
+	if x.Scale < 0 || x.Scale > maxScale {
+		return 0, false
+	}
+	unit := pow10(x.Scale).Int64()
+	return x.Units / unit, x.Units%unit == 0
+}
+
+// Converts an integral value to an int, for the bounds of a series
+func toInt(x Decimal) (int, bool) {
+	n, ok := integer(x)
+	return int(n), ok && n >= -math.MaxInt32 && n <= math.MaxInt32
+}
+
+func (c context) tokenMap() map[types.Keyword]types.KeywordData[Decimal] {
+	return map[types.Keyword]types.KeywordData[Decimal]{
+		Add: {Symbol: "+", TokenType: types.Operator,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				return c.round(new(big.Rat).Add(params[0].Rat(), params[1].Rat()))
+			}},
+		Subtract: {Symbol: "-", TokenType: types.Operator,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				return c.round(new(big.Rat).Sub(params[0].Rat(), params[1].Rat()))
+			}},
+		Multiply: {Symbol: "*", TokenType: types.Operator,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				return c.round(new(big.Rat).Mul(params[0].Rat(), params[1].Rat()))
+			}},
+		Divide: {Symbol: "/", TokenType: types.Operator,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				if params[1].Units == 0 {
+					return Decimal{}, types.ErrDivisionByZero
+				}
+				return c.round(new(big.Rat).Quo(params[0].Rat(), params[1].Rat()))
+			}},
+		Power: {Symbol: "^", TokenType: types.Operator,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				return c.pow(params[0], params[1])
+			}},
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: func(params ...Decimal) (Decimal, error) {
+				return c.round(new(big.Rat).Neg(params[0].Rat()))
+			}},
+		Sum: types.Series("sum", Decimal{Scale: c.scale}, func(x Decimal, y Decimal) (Decimal, error) {
+			return c.round(new(big.Rat).Add(x.Rat(), y.Rat()))
+		}, toInt),
+		Prod: types.Series("prod", Decimal{Units: c.unit.Int64(), Scale: c.scale}, func(x Decimal, y Decimal) (Decimal, error) {
+			return c.round(new(big.Rat).Mul(x.Rat(), y.Rat()))
+		}, toInt),
+	}
+}
+
+var decimalStringToToken = map[string]types.Keyword{
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"neg":  Negate,
+	"sum":  Sum,
+	"prod": Prod,
+}
+
+var decimalOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses decimal literals exactly, such as "19.99", and rounds them to the scale of the group
+func (c context) getValue(s string) (Decimal, bool) {
+	if strings.Contains(s, "/") {
+		return Decimal{}, false
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, false
+	}
+	d, err := c.round(r)
+	return d, err == nil
+}
+
+func newContext(scale int, mode RoundingMode) context {
+	if scale < 0 || scale > maxScale {
+		panic("Scale must be between 0 and 18")
+	}
+	return context{scale: scale, mode: mode, unit: pow10(scale)}
+}
+
+// New constructs a decimal group whose values have the given number of digits after the decimal point.
+// Every result, including literals, is rounded to that scale with the given rounding mode.
+// Results that do not fit are reported with ErrOverflow. The group is named after its scale and rounding mode,
+// such as "decimal(2,HalfEven)".
+func New(scale int, mode RoundingMode) *types.MathGroup[Decimal] {
+	c := newContext(scale, mode)
+	name := "decimal(" + strconv.Itoa(scale) + "," + mode.String() + ")"
+	return types.NewMathGroup(c.tokenMap(), decimalStringToToken, decimalOperatorPrecedence, c.getValue, types.WithName[Decimal](name), types.WithFormat(Decimal.String))
+}
+
+// Money represents amounts of money in cents, with banker's rounding.
+var Money = New(2, HalfEven)
+
+// Parse parses a decimal literal, rounded to the given scale with the given rounding mode.
+func Parse(s string, scale int, mode RoundingMode) (Decimal, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.Contains(s, "/") {
+		return Decimal{}, errors.New("invalid decimal " + s)
+	}
+	return newContext(scale, mode).round(r)
+}
+
+// NewInterval constructs a new decimal interval. The values must have the same scale.
+func NewInterval(start Decimal, step Decimal, end Decimal) *types.Interval[Decimal] {
+	if start.Scale != step.Scale || start.Scale != end.Scale || step.Units <= 0 || start.Units > end.Units {
+		panic("Invalid interval")
+	}
+	return &types.Interval[Decimal]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur Decimal) (Decimal, bool) {
+			if cur.Units > end.Units-step.Units {
+				return end, true
+			}
+			return Decimal{Units: cur.Units + step.Units, Scale: cur.Scale}, false
+		},
+	}
+}
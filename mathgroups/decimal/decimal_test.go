@@ -0,0 +1,97 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/decimal"
+	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
+)
+
+func testMapValuesHelper(group *types.MathGroup[decimal.Decimal], scale int, expression string, input string, expected string, t *testing.T) {
+	x, err := decimal.Parse(input, scale, decimal.Truncate)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	runnableDecimal := run.GetRunnableMathGroup(group)
+	r, err := runnableDecimal.MapValues(expression, *decimal.NewInterval(x, decimal.Decimal{Units: 1, Scale: scale}, x), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := r[0].String(); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper(decimal.Money, 2, "0.1 + 0.2", "0", "0.30", t)
+	testMapValuesHelper(decimal.Money, 2, "x * 3 - 0.01", "19.99", "59.96", t)
+	testMapValuesHelper(decimal.Money, 2, "-x / 4", "10", "-2.50", t)
+	testMapValuesHelper(decimal.Money, 2, "x * 1.05 * 1.05", "100", "110.25", t)
+	testMapValuesHelper(decimal.Money, 2, "x * 1.05^2", "100", "110.00", t)
+	testMapValuesHelper(decimal.Money, 2, "2^-2", "0", "0.25", t)
+	testMapValuesHelper(decimal.Money, 2, "0.5^100", "0", "0.00", t)
+	testMapValuesHelper(decimal.New(0, decimal.HalfEven), 0, "x * 2", "21", "42", t)
+}
+
+func TestRoundingModes(t *testing.T) {
+	for _, test := range []struct {
+		expression string
+		mode       decimal.RoundingMode
+		expected   string
+	}{
+		{"0.125 + 0", decimal.HalfEven, "0.12"},
+		{"0.135 + 0", decimal.HalfEven, "0.14"},
+		{"0.125 + 0", decimal.HalfUp, "0.13"},
+		{"-0.125 + 0", decimal.HalfUp, "-0.13"},
+		{"0.129 + 0", decimal.Truncate, "0.12"},
+		{"-2 / 3", decimal.Truncate, "-0.66"},
+		{"-2 / 3", decimal.HalfEven, "-0.67"},
+		{"1 / 8", decimal.HalfEven, "0.12"},
+		{"1 / 8", decimal.HalfUp, "0.13"},
+	} {
+		testMapValuesHelper(decimal.New(2, test.mode), 2, test.expression, "0", test.expected, t)
+	}
+}
+
+func TestName(t *testing.T) {
+	if decimal.Money.Name != "decimal(2,HalfEven)" || decimal.New(3, decimal.Truncate).Name != "decimal(3,Truncate)" {
+		t.Error("Unexpected names", decimal.Money.Name, decimal.New(3, decimal.Truncate).Name)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	runnableDecimal := run.GetRunnableMathGroup(decimal.Money)
+	zero := decimal.Decimal{Scale: 2}
+	for _, expression := range []string{"x / 0", "92233720368547758.07 + 0.01", "10^20", "x^0.5", "0^-1"} {
+		if _, err := runnableDecimal.MapValues(expression, *decimal.NewInterval(zero, decimal.Decimal{Units: 1, Scale: 2}, zero), "x"); err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for d, expected := range map[decimal.Decimal]string{
+		{Units: 5, Scale: 2}:                    "0.05",
+		{Units: -1234, Scale: 2}:                "-12.34",
+		{Units: 7, Scale: 0}:                    "7",
+		{Units: -9223372036854775808, Scale: 3}: "-9223372036854775.808",
+	} {
+		if output := d.String(); output != expected {
+			t.Errorf("String failed. Expected '%s', got '%s'", expected, output)
+		}
+	}
+}
+
+func TestSeries(t *testing.T) {
+	testMapValuesHelper(decimal.Money, 2, "sum(k, 1, x, 1 / 3)", "3", "0.99", t)
+	testMapValuesHelper(decimal.Money, 2, "prod(k, 1, x, 1.1)", "2", "1.21", t)
+	// Values built at another scale, such as by NewInterval, are read by their own scale
+	testMapValuesHelper(decimal.Money, 0, "sum(k, 1, x, k)", "3", "6.00", t)
+	testMapValuesHelper(decimal.Money, 0, "2^x", "3", "8.00", t)
+	testMapValuesHelper(decimal.Money, 4, "1.5^x", "2", "2.25", t)
+}
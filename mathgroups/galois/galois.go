@@ -0,0 +1,366 @@
+// Package galois is an implementation of finite fields GF(p^k) with common operators and functions.
+package galois
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Common functions and operations defined for galois fields
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Inverse
+)
+
+// The largest supported order, so that the log and exp tables stay small
+const maxOrder = 1 << 16
+
+// A field GF(p^k), with log and exp tables of a primitive element.
+// An element is an integer whose digits in base p are its coefficients as a polynomial.
+type field struct {
+	p   int
+	k   int
+	q   int   // The order, p^k
+	exp []int // exp[i] is g^i, for 0 <= i < 2(q - 1)
+	log []int // log[g^i] is i
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the coefficients of a polynomial, from the constant term upwards
+func toDigits(a int, p int, length int) []int {
+	digits := make([]int, length)
+	for i := range digits {
+		digits[i] = a % p
+		a /= p
+	}
+	return digits
+}
+
+func fromDigits(digits []int, p int) int {
+	a := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		a = a*p + digits[i]
+	}
+	return a
+}
+
+// Reduces the polynomial a modulo the monic polynomial m, in place, and returns the remainder
+func polyMod(a []int, m []int, p int) []int {
+	degree := len(m) - 1
+	for i := len(a) - 1; i >= degree; i-- {
+		c := a[i]
+		if c == 0 {
+			continue
+		}
+		for j, coefficient := range m {
+			a[i-degree+j] = ((a[i-degree+j]-c*coefficient)%p + p) % p
+		}
+	}
+	if len(a) > degree {
+		return a[:degree]
+	}
+	return a
+}
+
+// Returns true if the monic polynomial m has no factors of lower degree
+func isIrreducible(m []int, p int) bool {
+	degree := len(m) - 1
+	for d := 1; d <= degree/2; d++ {
+		count := 1
+		for i := 0; i < d; i++ {
+			count *= p
+		}
+		// Every monic polynomial of degree d
+		for lower := 0; lower < count; lower++ {
+			factor := append(toDigits(lower, p, d), 1)
+			remainder := polyMod(append([]int{}, m...), factor, p)
+			if fromDigits(remainder, p) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Multiplies two elements as polynomials modulo the irreducible polynomial, without the tables
+func (f *field) mulSlow(a int, b int, irreducible []int) int {
+	x := toDigits(a, f.p, f.k)
+	y := toDigits(b, f.p, f.k)
+	product := make([]int, 2*f.k)
+	for i, c := range x {
+		for j, d := range y {
+			product[i+j] = (product[i+j] + c*d) % f.p
+		}
+	}
+	return fromDigits(polyMod(product, irreducible, f.p), f.p)
+}
+
+func (f *field) powSlow(a int, n int, irreducible []int) int {
+	result := 1
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = f.mulSlow(result, a, irreducible)
+		}
+		a = f.mulSlow(a, a, irreducible)
+	}
+	return result
+}
+
+// Returns the distinct prime factors of n
+func primeFactors(n int) []int {
+	factors := []int{}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			factors = append(factors, d)
+			for n%d == 0 {
+				n /= d
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
+
+// Builds the log and exp tables from the first primitive element, whose powers are every nonzero element
+func (f *field) buildTables(irreducible []int) {
+	factors := primeFactors(f.q - 1)
+	g := 1
+	for ; g < f.q; g++ {
+		primitive := true
+		for _, r := range factors {
+			if f.powSlow(g, (f.q-1)/r, irreducible) == 1 {
+				primitive = false
+				break
+			}
+		}
+		if primitive {
+			break
+		}
+	}
+	f.exp = make([]int, 2*(f.q-1))
+	f.log = make([]int, f.q)
+	x := 1
+	for i := range f.exp {
+		f.exp[i] = x
+		if i < f.q-1 {
+			f.log[x] = i
+		}
+		x = f.mulSlow(x, g, irreducible)
+	}
+}
+
+func (f *field) check(params ...int) error {
+	for _, a := range params {
+		if a < 0 || a >= f.q {
+			return errors.New(strconv.Itoa(a) + " is not an element of GF(" + strconv.Itoa(f.q) + ")")
+		}
+	}
+	return nil
+}
+
+// Adds the coefficients of each power, modulo p
+func (f *field) add(a int, b int) int {
+	if f.p == 2 {
+		return a ^ b
+	}
+	sum, place := 0, 1
+	for ; a > 0 || b > 0; a, b, place = a/f.p, b/f.p, place*f.p {
+		sum += (a%f.p + b%f.p) % f.p * place
+	}
+	return sum
+}
+
+func (f *field) neg(a int) int {
+	if f.p == 2 {
+		return a
+	}
+	result, place := 0, 1
+	for ; a > 0; a, place = a/f.p, place*f.p {
+		result += (f.p - a%f.p) % f.p * place
+	}
+	return result
+}
+
+func (f *field) mul(a int, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.log[b]]
+}
+
+func (f *field) inverse(a int) (int, error) {
+	if a == 0 {
+		return 0, types.ErrDivisionByZero
+	}
+	return f.exp[(f.q-1-f.log[a])%(f.q-1)], nil
+}
+
+// Raises a to the power n, which is an integer rather than an element. Negative powers are powers of the inverse.
+func (f *field) pow(a int, n int) (int, error) {
+	if a == 0 {
+		switch {
+		case n < 0:
+			return 0, types.ErrDivisionByZero
+		case n == 0:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	// The nonzero elements have order q - 1, so n is reduced to 0 <= n < q - 1
+	n = (n%(f.q-1) + f.q - 1) % (f.q - 1)
+	return f.exp[f.log[a]*n%(f.q-1)], nil
+}
+
+func (f *field) tokenMap() map[types.Keyword]types.KeywordData[int] {
+	// Wraps an operation so that it checks its operands are elements
+	elementwise := func(apply func(...int) (int, error)) func(...int) (int, error) {
+		return func(params ...int) (int, error) {
+			if err := f.check(params...); err != nil {
+				return 0, err
+			}
+			return apply(params...)
+		}
+	}
+	return map[types.Keyword]types.KeywordData[int]{
+		Add: {Symbol: "+", TokenType: types.Operator,
+			Apply: elementwise(func(params ...int) (int, error) {
+				return f.add(params[0], params[1]), nil
+			})},
+		Subtract: {Symbol: "-", TokenType: types.Operator,
+			Apply: elementwise(func(params ...int) (int, error) {
+				return f.add(params[0], f.neg(params[1])), nil
+			})},
+		Multiply: {Symbol: "*", TokenType: types.Operator,
+			Apply: elementwise(func(params ...int) (int, error) {
+				return f.mul(params[0], params[1]), nil
+			})},
+		Divide: {Symbol: "/", TokenType: types.Operator,
+			Apply: elementwise(func(params ...int) (int, error) {
+				inverse, err := f.inverse(params[1])
+				if err != nil {
+					return 0, err
+				}
+				return f.mul(params[0], inverse), nil
+			})},
+		Power: {Symbol: "^", TokenType: types.Operator,
+			Apply: func(params ...int) (int, error) {
+				if err := f.check(params[0]); err != nil {
+					return 0, err
+				}
+				return f.pow(params[0], params[1])
+			}},
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: elementwise(func(params ...int) (int, error) {
+				return f.neg(params[0]), nil
+			})},
+		Inverse: {Symbol: "inv", TokenType: types.SingleFunction,
+			Apply: elementwise(func(params ...int) (int, error) {
+				return f.inverse(params[0])
+			})},
+	}
+}
+
+var galoisStringToToken = map[string]types.Keyword{
+	"+":   Add,
+	"-":   Subtract,
+	"*":   Multiply,
+	"/":   Divide,
+	"^":   Power,
+	"neg": Negate,
+	"inv": Inverse,
+}
+
+var galoisOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses non-negative integer literals, such as "27" or "0x1b". Literals are not checked to be elements,
+// so that they can be used as exponents larger than the order.
+func getInteger(s string) (int, bool) {
+	n, err := strconv.ParseInt(s, 0, 32)
+	return int(n), err == nil && n >= 0
+}
+
+// Formats elements in hexadecimal for characteristic 2, such as "0x1b", and in decimal otherwise
+func (f *field) formatValue(a int) string {
+	if f.p == 2 {
+		return "0x" + strconv.FormatInt(int64(a), 16)
+	}
+	return strconv.Itoa(a)
+}
+
+// New constructs the field GF(p^k) with the given characteristic p and degree k, as polynomials over GF(p)
+// modulo the given monic irreducible polynomial of degree k. Polynomials are written as integers whose digits
+// in base p are their coefficients, so x^8 + x^4 + x^3 + x + 1 over GF(2) is 0x11b.
+//
+// Multiplication and division use log and exp tables, so the order p^k can be at most 65536.
+// The exponent of ^ is used as an integer rather than an element, so it is not negated by -; use inv instead.
+// The group is named after its order and irreducible polynomial, such as "GF(256,0x11b)".
+func New(characteristic int, degree int, irreducible int) *types.MathGroup[int] {
+	if !isPrime(characteristic) {
+		panic("Characteristic must be prime")
+	}
+	f := &field{p: characteristic, k: degree, q: 1}
+	for i := 0; i < degree; i++ {
+		if f.q > maxOrder/characteristic {
+			panic("Order must be at most 65536")
+		}
+		f.q *= characteristic
+	}
+	if degree < 1 || irreducible/f.q != 1 {
+		panic("Irreducible polynomial must be monic, with the given degree")
+	}
+	m := toDigits(irreducible, f.p, f.k+1)
+	if !isIrreducible(m, f.p) {
+		panic("Polynomial is not irreducible")
+	}
+	f.buildTables(m)
+	name := "GF(" + strconv.Itoa(f.q) + "," + f.formatValue(irreducible) + ")"
+	return types.NewMathGroup(f.tokenMap(), galoisStringToToken, galoisOperatorPrecedence, getInteger, types.WithName[int](name), types.WithFormat(f.formatValue))
+}
+
+// AES represents GF(2^8) with the irreducible polynomial x^8 + x^4 + x^3 + x + 1, as used by AES.
+var AES = New(2, 8, 0x11b)
+
+// NewInterval constructs a new interval of integers, such as the elements of a field.
+func NewInterval(start int, step int, end int) *types.Interval[int] {
+	if step <= 0 || start > end {
+		panic("Invalid interval")
+	}
+	return &types.Interval[int]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur int) (int, bool) {
+			if cur > end-step {
+				return end, true
+			}
+			return cur + step, false
+		},
+	}
+}
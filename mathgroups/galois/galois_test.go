@@ -0,0 +1,87 @@
+package galois_test
+
+import (
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/galois"
+	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
+)
+
+func testMapValuesHelper(group *types.MathGroup[int], expression string, input int, expected string, t *testing.T) {
+	runnableGalois := run.GetRunnableMathGroup(group)
+	r, err := runnableGalois.MapValues(expression, *galois.NewInterval(input, 1, input), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := group.FormatValue(r[0]); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestAES(t *testing.T) {
+	testMapValuesHelper(galois.AES, "0x57 + 0x83", 0, "0xd4", t)
+	testMapValuesHelper(galois.AES, "0x57 * 0x83", 0, "0xc1", t)
+	testMapValuesHelper(galois.AES, "0x57 * x", 0x13, "0xfe", t)
+	testMapValuesHelper(galois.AES, "inv(x)", 0x53, "0xca", t)
+	testMapValuesHelper(galois.AES, "x / x", 0x53, "0x1", t)
+	testMapValuesHelper(galois.AES, "0xc1 / 0x83", 0, "0x57", t)
+	testMapValuesHelper(galois.AES, "x^255", 0x1b, "0x1", t)
+	testMapValuesHelper(galois.AES, "x^2 - x*x", 0x1b, "0x0", t)
+	testMapValuesHelper(galois.AES, "-x", 0x1b, "0x1b", t)
+}
+
+func TestPrimePower(t *testing.T) {
+	// GF(9) as polynomials over GF(3) modulo x^2 + 1, where 3 is x
+	gf9 := galois.New(3, 2, 10)
+	testMapValuesHelper(gf9, "x * x", 3, "2", t)
+	testMapValuesHelper(gf9, "x + x + x", 5, "0", t)
+	testMapValuesHelper(gf9, "-x", 5, "7", t)
+	testMapValuesHelper(gf9, "x^8", 5, "1", t)
+	testMapValuesHelper(gf9, "inv(x) * x", 7, "1", t)
+
+	gf7 := galois.New(7, 1, 7)
+	testMapValuesHelper(gf7, "3 * 5 - 1 / 2", 0, "4", t)
+}
+
+func TestErrors(t *testing.T) {
+	runnableGalois := run.GetRunnableMathGroup(galois.AES)
+	for _, expression := range []string{"x / 0", "inv(0)", "256 + 1", "0x100^2"} {
+		if _, err := runnableGalois.MapValues(expression, *galois.NewInterval(0, 1, 0), "x"); err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+}
+
+func TestNegativePowers(t *testing.T) {
+	testMapValuesHelper(galois.AES, "2^x", -1, "0x8d", t)
+	testMapValuesHelper(galois.AES, "0x57^x * 0x57^2", -2, "0x1", t)
+	testMapValuesHelper(galois.AES, "3^x", -255, "0x1", t)
+	testMapValuesHelper(galois.New(3, 2, 10), "2^x", -1, "2", t)
+	runnableGalois := run.GetRunnableMathGroup(galois.AES)
+	if _, err := runnableGalois.MapValues("0^x", *galois.NewInterval(-1, 1, -1), "x"); err == nil {
+		t.Error("Expected an error on a negative power of 0")
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for _, args := range [][3]int{{4, 2, 0x13}, {2, 2, 0x5}, {2, 8, 0x1b}, {2, 17, 1 << 17}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected New to panic for", args)
+				}
+			}()
+			galois.New(args[0], args[1], args[2])
+		}()
+	}
+}
+
+func TestName(t *testing.T) {
+	if galois.AES.Name != "GF(256,0x11b)" || galois.New(2, 8, 0x11d).Name != "GF(256,0x11d)" || galois.New(3, 2, 10).Name != "GF(9,10)" {
+		t.Error("Unexpected names", galois.AES.Name, galois.New(2, 8, 0x11d).Name, galois.New(3, 2, 10).Name)
+	}
+}
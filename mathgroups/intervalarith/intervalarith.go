@@ -0,0 +1,462 @@
+// Package intervalarith is an implementation of interval arithmetic, where each value is a closed interval
+// guaranteed to enclose the exact result. Every result is rounded outwards.
+package intervalarith
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Interval is the closed interval [Lo, Hi].
+type Interval struct {
+	Lo float64
+	Hi float64
+}
+
+// Common functions and operations defined for the interval group, matching the real group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Sin
+	Cos
+	Tan
+	Log
+	Exp
+	Factorial
+	Percent
+	Not
+	Negate
+	Less
+	Greater
+	LessEqual
+	GreaterEqual
+	Equal
+	NotEqual
+	And
+	Or
+	Hull
+)
+
+// Unit in the last place allowed for the error of each result.
+// Arithmetic is correctly rounded, while math library functions are allowed a larger error.
+const (
+	arithmeticULPs = 1
+	functionULPs   = 4
+)
+
+// Widens an interval outwards by the given number of units in the last place
+func outward(lo float64, hi float64, ulps int) Interval {
+	for i := 0; i < ulps; i++ {
+		lo = math.Nextafter(lo, math.Inf(-1))
+		hi = math.Nextafter(hi, math.Inf(1))
+	}
+	return Interval{lo, hi}
+}
+
+// Point returns the interval containing only x.
+func Point(x float64) Interval {
+	return Interval{x, x}
+}
+
+// Contains returns true if x is in the interval.
+func (i Interval) Contains(x float64) bool {
+	return i.Lo <= x && x <= i.Hi
+}
+
+// Width returns the width of the interval.
+func (i Interval) Width() float64 {
+	return i.Hi - i.Lo
+}
+
+// Min and max of several values, ignoring NaN from 0 * Inf
+func bounds(values ...float64) (lo float64, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			v = 0
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+// Encloses a monotonically increasing function applied to an interval
+func increasing(f func(float64) float64, x Interval) Interval {
+	return outward(f(x.Lo), f(x.Hi), functionULPs)
+}
+
+// Returns true if the interval contains offset + k period for some integer k.
+// Near misses are counted as contained, which only makes results wider.
+func containsPeriodic(x Interval, offset float64, period float64) bool {
+	k := math.Ceil((x.Lo-offset)/period - 1e-9)
+	return offset+k*period <= x.Hi+1e-9*period
+}
+
+func sin(x Interval) Interval {
+	if x.Width() >= 2*math.Pi {
+		return Interval{-1, 1}
+	}
+	result := outward(math.Min(math.Sin(x.Lo), math.Sin(x.Hi)), math.Max(math.Sin(x.Lo), math.Sin(x.Hi)), functionULPs)
+	if containsPeriodic(x, math.Pi/2, 2*math.Pi) {
+		result.Hi = 1
+	}
+	if containsPeriodic(x, -math.Pi/2, 2*math.Pi) {
+		result.Lo = -1
+	}
+	return clamp(result)
+}
+
+func cos(x Interval) Interval {
+	if x.Width() >= 2*math.Pi {
+		return Interval{-1, 1}
+	}
+	result := outward(math.Min(math.Cos(x.Lo), math.Cos(x.Hi)), math.Max(math.Cos(x.Lo), math.Cos(x.Hi)), functionULPs)
+	if containsPeriodic(x, 0, 2*math.Pi) {
+		result.Hi = 1
+	}
+	if containsPeriodic(x, math.Pi, 2*math.Pi) {
+		result.Lo = -1
+	}
+	return clamp(result)
+}
+
+// Keeps the result of sin or cos within [-1, 1]
+func clamp(x Interval) Interval {
+	return Interval{math.Max(x.Lo, -1), math.Min(x.Hi, 1)}
+}
+
+func tan(x Interval) (Interval, error) {
+	if x.Width() >= math.Pi || containsPeriodic(x, math.Pi/2, math.Pi) {
+		return Interval{}, errors.New("tan is undefined on an interval containing a pole")
+	}
+	return increasing(math.Tan, x), nil
+}
+
+func log(x Interval) (Interval, error) {
+	if x.Lo <= 0 {
+		return Interval{}, errors.New("logarithm of an interval containing non-positive numbers")
+	}
+	return increasing(math.Log, x), nil
+}
+
+func multiply(x Interval, y Interval) Interval {
+	lo, hi := bounds(x.Lo*y.Lo, x.Lo*y.Hi, x.Hi*y.Lo, x.Hi*y.Hi)
+	return outward(lo, hi, arithmeticULPs)
+}
+
+func divide(x Interval, y Interval) (Interval, error) {
+	if y.Contains(0) {
+		return Interval{}, types.ErrDivisionByZero
+	}
+	lo, hi := bounds(x.Lo/y.Lo, x.Lo/y.Hi, x.Hi/y.Lo, x.Hi/y.Hi)
+	return outward(lo, hi, arithmeticULPs), nil
+}
+
+// Encloses x^n for n >= 0 by square-and-multiply, so that every step is rounded outwards
+func pointPow(x float64, n int) Interval {
+	result, square := Point(1), Point(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = multiply(result, square)
+		}
+		if n > 1 {
+			square = multiply(square, square)
+		}
+	}
+	return result
+}
+
+// Raises an interval to an integer power. Odd powers are increasing, and even powers are increasing in the
+// absolute value, so only the powers of the endpoints are needed.
+func intPow(x Interval, n int) (Interval, error) {
+	if n < 0 {
+		positive, err := intPow(x, -n)
+		if err != nil {
+			return Interval{}, err
+		}
+		return divide(Point(1), positive)
+	}
+	if n%2 == 1 {
+		return Interval{pointPow(x.Lo, n).Lo, pointPow(x.Hi, n).Hi}, nil
+	}
+	lo, hi := math.Abs(x.Lo), math.Abs(x.Hi)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if x.Contains(0) {
+		lo = 0
+	}
+	return Interval{math.Max(pointPow(lo, n).Lo, 0), pointPow(hi, n).Hi}, nil
+}
+
+func pow(x Interval, y Interval) (Interval, error) {
+	if y.Lo == y.Hi && y.Lo == math.Trunc(y.Lo) && math.Abs(y.Lo) < 1<<31 {
+		return intPow(x, int(y.Lo))
+	}
+	logX, err := log(x)
+	if err != nil {
+		return Interval{}, errors.New("power of an interval containing non-positive numbers must have an integer exponent")
+	}
+	return increasing(math.Exp, multiply(y, logX)), nil
+}
+
+// The positive minimum of the gamma function is at gammaMinX, and is at least gammaMin
+const (
+	gammaMinX = 1.4616321449683623
+	gammaMin  = 0.8856031944108886
+)
+
+// Encloses x! = gamma(x + 1), for x > -1
+func factorial(x Interval) (Interval, error) {
+	t := outward(x.Lo+1, x.Hi+1, arithmeticULPs)
+	if t.Lo <= 0 {
+		return Interval{}, errors.New("factorial is only defined here for numbers greater than -1")
+	}
+	lo, hi := bounds(math.Gamma(t.Lo), math.Gamma(t.Hi))
+	if t.Contains(gammaMinX) {
+		lo = gammaMin
+	}
+	return outward(lo, hi, functionULPs), nil
+}
+
+// Results of comparisons and logic: certainly true, certainly false, or either
+var (
+	certainlyTrue  = Interval{1, 1}
+	certainlyFalse = Interval{0, 0}
+	uncertain      = Interval{0, 1}
+)
+
+func fromCertainty(isTrue bool, isFalse bool) Interval {
+	switch {
+	case isTrue:
+		return certainlyTrue
+	case isFalse:
+		return certainlyFalse
+	}
+	return uncertain
+}
+
+// A value is certainly true if it cannot be 0, and certainly false if it can only be 0
+func truth(x Interval) (isTrue bool, isFalse bool) {
+	return !x.Contains(0), x.Lo == 0 && x.Hi == 0
+}
+
+var intervalTokenMap = map[types.Keyword]types.KeywordData[Interval]{
+	Add: {Symbol: "+", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return outward(params[0].Lo+params[1].Lo, params[0].Hi+params[1].Hi, arithmeticULPs), nil
+		}},
+	Subtract: {Symbol: "-", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return outward(params[0].Lo-params[1].Hi, params[0].Hi-params[1].Lo, arithmeticULPs), nil
+		}},
+	Multiply: {Symbol: "*", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return multiply(params[0], params[1]), nil
+		}},
+	Divide: {Symbol: "/", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return divide(params[0], params[1])
+		}},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return pow(params[0], params[1])
+		}},
+	Sin: {Symbol: "sin", TokenType: types.SingleFunction,
+		Apply: func(params ...Interval) (Interval, error) {
+			return sin(params[0]), nil
+		}},
+	Cos: {Symbol: "cos", TokenType: types.SingleFunction,
+		Apply: func(params ...Interval) (Interval, error) {
+			return cos(params[0]), nil
+		}},
+	Tan: {Symbol: "tan", TokenType: types.SingleFunction,
+		Apply: func(params ...Interval) (Interval, error) {
+			return tan(params[0])
+		}},
+	Log: {Symbol: "log", TokenType: types.SingleFunction,
+		Apply: func(params ...Interval) (Interval, error) {
+			return log(params[0])
+		}},
+	Exp: {Symbol: "exp", TokenType: types.SingleFunction,
+		Apply: func(params ...Interval) (Interval, error) {
+			return increasing(math.Exp, params[0]), nil
+		}},
+	Factorial: {Symbol: "!", TokenType: types.Operator, Fixity: types.Postfix,
+		Apply: func(params ...Interval) (Interval, error) {
+			return factorial(params[0])
+		}},
+	Percent: {Symbol: "%", TokenType: types.Operator, Fixity: types.Postfix,
+		Apply: func(params ...Interval) (Interval, error) {
+			return divide(params[0], Point(100))
+		}},
+	Not: {Symbol: "not", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Interval) (Interval, error) {
+			isTrue, isFalse := truth(params[0])
+			return fromCertainty(isFalse, isTrue), nil
+		}},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Interval) (Interval, error) {
+			return Interval{-params[0].Hi, -params[0].Lo}, nil
+		}},
+	Less: {Symbol: "<", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return fromCertainty(params[0].Hi < params[1].Lo, params[0].Lo >= params[1].Hi), nil
+		}},
+	Greater: {Symbol: ">", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return fromCertainty(params[0].Lo > params[1].Hi, params[0].Hi <= params[1].Lo), nil
+		}},
+	LessEqual: {Symbol: "<=", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return fromCertainty(params[0].Hi <= params[1].Lo, params[0].Lo > params[1].Hi), nil
+		}},
+	GreaterEqual: {Symbol: ">=", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			return fromCertainty(params[0].Lo >= params[1].Hi, params[0].Hi < params[1].Lo), nil
+		}},
+	Equal: {Symbol: "==", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			x, y := params[0], params[1]
+			same := x.Lo == x.Hi && x == y
+			return fromCertainty(same, x.Hi < y.Lo || y.Hi < x.Lo), nil
+		}},
+	NotEqual: {Symbol: "!=", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			x, y := params[0], params[1]
+			same := x.Lo == x.Hi && x == y
+			return fromCertainty(x.Hi < y.Lo || y.Hi < x.Lo, same), nil
+		}},
+	And: {Symbol: "&&", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			xTrue, xFalse := truth(params[0])
+			yTrue, yFalse := truth(params[1])
+			return fromCertainty(xTrue && yTrue, xFalse || yFalse), nil
+		}},
+	Or: {Symbol: "||", TokenType: types.Operator,
+		Apply: func(params ...Interval) (Interval, error) {
+			xTrue, xFalse := truth(params[0])
+			yTrue, yFalse := truth(params[1])
+			return fromCertainty(xTrue || yTrue, xFalse && yFalse), nil
+		}},
+	Hull: {Symbol: "hull", TokenType: types.SingleFunction, Arity: 2,
+		Apply: func(params ...Interval) (Interval, error) {
+			return Interval{math.Min(params[0].Lo, params[1].Lo), math.Max(params[0].Hi, params[1].Hi)}, nil
+		}},
+}
+
+var intervalStringToToken = map[string]types.Keyword{
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"sin":  Sin,
+	"cos":  Cos,
+	"tan":  Tan,
+	"log":  Log,
+	"exp":  Exp,
+	"!":    Factorial,
+	"%":    Percent,
+	"not":  Not,
+	"neg":  Negate,
+	"<":    Less,
+	">":    Greater,
+	"<=":   LessEqual,
+	">=":   GreaterEqual,
+	"==":   Equal,
+	"!=":   NotEqual,
+	"&&":   And,
+	"||":   Or,
+	"hull": Hull,
+}
+
+var intervalOperatorPrecedence = map[types.Keyword]int{
+	Or:           1,
+	And:          2,
+	Not:          3,
+	Less:         4,
+	Greater:      4,
+	LessEqual:    4,
+	GreaterEqual: 4,
+	Equal:        4,
+	NotEqual:     4,
+	Add:          5,
+	Subtract:     5,
+	Multiply:     6,
+	Divide:       6,
+	Negate:       6,
+	Power:        7,
+	Factorial:    8,
+	Percent:      8,
+}
+
+// Parses a decimal literal into the smallest interval enclosing it, along with the constant "pi"
+func getInterval(s string) (Interval, bool) {
+	if s == "pi" {
+		return Interval{math.Pi, math.Nextafter(math.Pi, math.Inf(1))}, true
+	}
+	exact, _, err := big.ParseFloat(s, 10, 1024, big.ToNearestEven)
+	if err != nil {
+		return Interval{}, false
+	}
+	x, accuracy := exact.Float64()
+	switch accuracy {
+	case big.Below:
+		return Interval{x, math.Nextafter(x, math.Inf(1))}, true
+	case big.Above:
+		return Interval{math.Nextafter(x, math.Inf(-1)), x}, true
+	}
+	return Point(x), true
+}
+
+// Formats an interval as "[lo, hi]", or as a single number if it is a point
+func formatInterval(x Interval) string {
+	lo := strconv.FormatFloat(x.Lo, 'g', -1, 64)
+	if x.Lo == x.Hi {
+		return lo
+	}
+	return "[" + lo + ", " + strconv.FormatFloat(x.Hi, 'g', -1, 64) + "]"
+}
+
+// IntervalArith represents interval arithmetic over float64, with the operations and functions of the real group.
+// Comparisons and logic result in [1, 1] when certainly true, [0, 0] when certainly false, and [0, 1] otherwise.
+// There is no conditional, since a condition may be neither certainly true nor certainly false.
+// Intervals can be written in expressions with hull, such as hull(1, 2).
+var IntervalArith = types.NewMathGroup(intervalTokenMap, intervalStringToToken, intervalOperatorPrecedence, getInterval, types.WithName[Interval]("intervalarith"), types.WithFormat(formatInterval))
+
+// NewSubdivision constructs an interval of values that split [lo, hi] into the given number of equal pieces.
+// Evaluating over the subdivision encloses the range of an expression on each piece.
+func NewSubdivision(lo float64, hi float64, pieces int) *types.Interval[Interval] {
+	if pieces <= 0 || lo > hi {
+		panic("Invalid interval")
+	}
+	width := (hi - lo) / float64(pieces)
+	// Each piece is found from its index, so that rounding errors do not accumulate
+	piece := func(i int) Interval {
+		if i == pieces-1 {
+			return Interval{lo + float64(i)*width, hi}
+		}
+		return Interval{lo + float64(i)*width, lo + float64(i+1)*width}
+	}
+	return &types.Interval[Interval]{
+		Start: piece(0),
+		Step:  Point(width),
+		End:   piece(pieces - 1),
+		Next: func(cur Interval) (Interval, bool) {
+			i := int(math.Round((cur.Lo-lo)/width)) + 1
+			if i >= pieces {
+				return piece(pieces - 1), true
+			}
+			return piece(i), false
+		},
+	}
+}
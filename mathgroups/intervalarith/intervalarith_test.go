@@ -0,0 +1,84 @@
+package intervalarith_test
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/yasteen/go-parse/evaluate"
+	"github.com/yasteen/go-parse/mathgroups/intervalarith"
+	"github.com/yasteen/go-parse/parsexp"
+)
+
+// Checks that the enclosure contains the exact range, and is no wider than the exact range plus the tolerance
+func testEncloseHelper(expression string, input intervalarith.Interval, lo float64, hi float64, t *testing.T) {
+	parsed, err := parsexp.Parse(expression, "x", intervalarith.IntervalArith)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	result, err := evaluate.Once(parsed, input, intervalarith.IntervalArith)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	const tolerance = 1e-12
+	if result.Lo > lo || result.Hi < hi || result.Lo < lo-tolerance || result.Hi > hi+tolerance {
+		t.Error("Bad enclosure of", expression, "- Expected:", lo, hi, "Got:", result)
+	}
+}
+
+func TestEnclose(t *testing.T) {
+	testEncloseHelper("x + 0.1", intervalarith.Interval{Lo: 1, Hi: 2}, 1.1, 2.1, t)
+	testEncloseHelper("x * x", intervalarith.Interval{Lo: -1, Hi: 2}, -2, 4, t)
+	testEncloseHelper("x ^ 2", intervalarith.Interval{Lo: -1, Hi: 2}, 0, 4, t)
+	testEncloseHelper("x - x", intervalarith.Interval{Lo: 0, Hi: 1}, -1, 1, t)
+	testEncloseHelper("1 / x", intervalarith.Interval{Lo: 2, Hi: 4}, 0.25, 0.5, t)
+	testEncloseHelper("sin(x)", intervalarith.Interval{Lo: 0, Hi: 3}, 0, 1, t)
+	testEncloseHelper("cos(x)", intervalarith.Interval{Lo: 1, Hi: 4}, -1, math.Cos(1), t)
+	testEncloseHelper("exp(x) - log(x)", intervalarith.Interval{Lo: 1, Hi: 2}, math.E-math.Log(2), math.Exp(2), t)
+	testEncloseHelper("x!", intervalarith.Interval{Lo: 0, Hi: 3}, 0.8856031944108886, 6, t)
+	testEncloseHelper("hull(x, 5) * pi", intervalarith.Interval{Lo: 1, Hi: 1}, math.Pi, 5*math.Pi, t)
+	testEncloseHelper("x > 1", intervalarith.Interval{Lo: 0, Hi: 2}, 0, 1, t)
+	testEncloseHelper("x > 1 && x < 3", intervalarith.Interval{Lo: 2, Hi: 2.5}, 1, 1, t)
+}
+
+// Checks integer powers against the exact power of a float64, which has at most 53 n bits
+func TestPowerEnclosure(t *testing.T) {
+	for _, test := range []struct {
+		x float64
+		n int
+	}{{1.474983327064277, 569}, {-1.474983327064277, 569}, {-1.1, 1000}, {0.9999999999999999, 999}} {
+		parsed, _ := parsexp.Parse("x ^ "+strconv.Itoa(test.n), "x", intervalarith.IntervalArith)
+		result, err := evaluate.Once(parsed, intervalarith.Point(test.x), intervalarith.IntervalArith)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		exact := new(big.Float).SetPrec(uint(53 * test.n)).SetInt64(1)
+		for i := 0; i < test.n; i++ {
+			exact.Mul(exact, big.NewFloat(test.x))
+		}
+		if exact.Cmp(big.NewFloat(result.Lo)) < 0 || exact.Cmp(big.NewFloat(result.Hi)) > 0 {
+			t.Error("Bad enclosure of", test.x, "^", test.n, "- Expected:", exact.Text('g', 20), "Got:", result)
+		}
+		if result.Width() > 1e-12*math.Abs(result.Hi) {
+			t.Error("Enclosure of", test.x, "^", test.n, "is too wide:", result)
+		}
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	parsed, _ := parsexp.Parse("1 / x", "x", intervalarith.IntervalArith)
+	if _, err := evaluate.Once(parsed, intervalarith.Interval{Lo: -1, Hi: 1}, intervalarith.IntervalArith); err == nil {
+		t.Error("Failed to detect division by an interval containing zero.")
+	}
+}
+
+func TestSubdivision(t *testing.T) {
+	values := intervalarith.NewSubdivision(0, 1, 4).Values()
+	if len(values) != 4 || values[0].Lo != 0 || values[3].Hi != 1 || values[1].Lo != values[0].Hi {
+		t.Error("Subdivision produced the wrong pieces:", values)
+	}
+}
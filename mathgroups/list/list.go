@@ -0,0 +1,386 @@
+// Package list lifts a group into lists of its values, with ranges and higher-order functions such as map and filter.
+package list
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/types"
+)
+
+// Value is either a scalar of the element group, or a list of them.
+type Value[T any] struct {
+	Scalar T
+	List   []T
+	IsList bool
+}
+
+// Scalar constructs a scalar value.
+func Scalar[T any](x T) Value[T] {
+	return Value[T]{Scalar: x}
+}
+
+// Of constructs a list of the given elements.
+func Of[T any](elements ...T) Value[T] {
+	if elements == nil {
+		elements = []T{}
+	}
+	return Value[T]{List: elements, IsList: true}
+}
+
+// Functions and operations defined for lists. The keywords of the element group follow them.
+const (
+	Range types.Keyword = iota
+	Len
+	Sum
+	Mean
+	Map
+	Filter
+	Reduce
+	elementKeywords // The first keyword of the element group
+)
+
+// ErrNotList is returned when a list function is applied to a scalar.
+var ErrNotList = errors.New("expected a list")
+
+// The largest number of elements in a range, so that a mistyped bound does not exhaust memory
+const maxRange = 1 << 20
+
+// A list group, and the element group it lifts
+type group[T any] struct {
+	m *types.MathGroup[T]
+}
+
+// Applies an operation of the element group to scalars, or to each element of lists of the same length,
+// with each scalar used for every element
+func broadcast[T any](apply func(...T) (T, error)) func(...Value[T]) (Value[T], error) {
+	return func(params ...Value[T]) (Value[T], error) {
+		length := -1
+		for _, p := range params {
+			if p.IsList && length >= 0 && len(p.List) != length {
+				return Value[T]{}, errors.New("lists have different lengths")
+			} else if p.IsList {
+				length = len(p.List)
+			}
+		}
+		args := make([]T, len(params))
+		if length < 0 {
+			for i, p := range params {
+				args[i] = p.Scalar
+			}
+			x, err := apply(args...)
+			return Scalar(x), err
+		}
+		result := make([]T, length)
+		for j := range result {
+			for i, p := range params {
+				if p.IsList {
+					args[i] = p.List[j]
+				} else {
+					args[i] = p.Scalar
+				}
+			}
+			x, err := apply(args...)
+			if err != nil {
+				return Value[T]{}, err
+			}
+			result[j] = x
+		}
+		return Of(result...), nil
+	}
+}
+
+// Lifts each keyword of the element group that is not shadowed by a list keyword. Keywords that bind
+// variables, such as series, are not lifted.
+func (g group[T]) liftKeywords(tokenMap map[types.Keyword]types.KeywordData[Value[T]], stringToToken map[string]types.Keyword, precedence map[types.Keyword]int) {
+	for _, s := range g.m.Tokens() {
+		_, keyword := g.m.StringToTokenType(s)
+		keywordData, ok := g.m.Data(keyword)
+		if _, shadowed := stringToToken[s]; shadowed || !ok || keywordData.Index != nil || keywordData.Lambda != nil {
+			continue
+		}
+		lifted := keyword + elementKeywords
+		stringToToken[s] = lifted
+		if p := g.m.Precedence(keyword); p != 0 {
+			precedence[lifted] = p
+		}
+		var condition func(Value[T]) bool
+		var validate func(Value[T]) error
+		if keywordData.Condition != nil {
+			condition = func(v Value[T]) bool {
+				return !v.IsList && keywordData.Condition(v.Scalar)
+			}
+			// A list can not choose a single branch, so it is an error rather than false
+			validate = func(v Value[T]) error {
+				if v.IsList {
+					return errors.New("the condition of " + keywordData.Symbol + " must be a single value, not a list")
+				}
+				return nil
+			}
+		}
+		tokenMap[lifted] = types.KeywordData[Value[T]]{
+			Symbol:        keywordData.Symbol,
+			TokenType:     keywordData.TokenType,
+			Fixity:        keywordData.Fixity,
+			Associativity: keywordData.Associativity,
+			Implicit:      keywordData.Implicit,
+			Arity:         keywordData.Arity,
+			Apply:         broadcast(keywordData.Apply),
+			Condition:     condition,
+			Validate:      validate,
+		}
+	}
+}
+
+// Converts an integer into a value of the element group
+func (g group[T]) fromInt(n int) (T, error) {
+	x, ok := g.m.GetValue(strconv.Itoa(n))
+	if !ok {
+		return x, errors.New(strconv.Itoa(n) + " is not a value of " + g.m.Name)
+	}
+	return x, nil
+}
+
+// Returns the list a..b of the integers from a to b
+func (g group[T]) rangeList(a Value[T], b Value[T]) (Value[T], error) {
+	lo, ok := g.m.ToInteger(a.Scalar)
+	hi, ok2 := g.m.ToInteger(b.Scalar)
+	if a.IsList || b.IsList || !ok || !ok2 {
+		return Value[T]{}, errors.New("the bounds of a range must be integers")
+	}
+	if hi-lo >= maxRange {
+		return Value[T]{}, errors.New("range has more than " + strconv.Itoa(maxRange) + " elements")
+	}
+	elements := []T{}
+	for n := lo; n <= hi; n++ {
+		x, err := g.fromInt(n)
+		if err != nil {
+			return Value[T]{}, err
+		}
+		elements = append(elements, x)
+	}
+	return Of(elements...), nil
+}
+
+// Adds the elements of a list with the element group's sum series
+func (g group[T]) sum(sum types.Keyword, list Value[T]) (T, error) {
+	result, err := g.m.ApplyKeyword(sum)
+	for _, x := range list.List {
+		if err != nil {
+			break
+		}
+		result, err = g.m.ApplyKeyword(sum, result, x)
+	}
+	return result, err
+}
+
+func (g group[T]) tokenMap() (map[types.Keyword]types.KeywordData[Value[T]], map[string]types.Keyword, map[types.Keyword]int) {
+	tokenMap := map[types.Keyword]types.KeywordData[Value[T]]{
+		Range: {Symbol: "..", TokenType: types.Operator, Apply: func(params ...Value[T]) (Value[T], error) {
+			return g.rangeList(params[0], params[1])
+		}},
+		Len: {Symbol: "len", TokenType: types.SingleFunction, Apply: func(params ...Value[T]) (Value[T], error) {
+			if !params[0].IsList {
+				return Value[T]{}, ErrNotList
+			}
+			x, err := g.fromInt(len(params[0].List))
+			return Scalar(x), err
+		}},
+		// map(v, body, list) is the list of the values of body, with v bound to each element
+		Map: {Symbol: "map", TokenType: types.SingleFunction, Arity: 3, Binds: 1,
+			Lambda: func(body func(...Value[T]) (Value[T], error), params ...Value[T]) (Value[T], error) {
+				if !params[0].IsList {
+					return Value[T]{}, ErrNotList
+				}
+				result := make([]T, len(params[0].List))
+				for i, x := range params[0].List {
+					value, err := body(Scalar(x))
+					if err != nil {
+						return Value[T]{}, err
+					}
+					if value.IsList {
+						return Value[T]{}, errors.New("the body of map must be a scalar")
+					}
+					result[i] = value.Scalar
+				}
+				return Of(result...), nil
+			}},
+		// reduce(acc, v, body, initial, list) is the value of body, with acc bound to initial for the first
+		// element and to the previous value of body for the rest, and v bound to each element in turn
+		Reduce: {Symbol: "reduce", TokenType: types.SingleFunction, Arity: 5, Binds: 2,
+			Lambda: func(body func(...Value[T]) (Value[T], error), params ...Value[T]) (Value[T], error) {
+				if !params[1].IsList {
+					return Value[T]{}, ErrNotList
+				}
+				result := params[0]
+				for _, x := range params[1].List {
+					var err error
+					if result, err = body(result, Scalar(x)); err != nil {
+						return Value[T]{}, err
+					}
+				}
+				return result, nil
+			}},
+	}
+	stringToToken := map[string]types.Keyword{
+		"..":     Range,
+		"len":    Len,
+		"map":    Map,
+		"reduce": Reduce,
+	}
+	precedence := map[types.Keyword]int{
+		Range: 0,
+	}
+
+	// sum and mean use the element group's sum series, and mean also its division
+	_, sum := g.m.StringToTokenType("sum")
+	divideType, divide := g.m.StringToTokenType("/")
+	if g.m.Index(sum) != nil {
+		tokenMap[Sum] = types.KeywordData[Value[T]]{Symbol: "sum", TokenType: types.SingleFunction,
+			Apply: func(params ...Value[T]) (Value[T], error) {
+				if !params[0].IsList {
+					return Value[T]{}, ErrNotList
+				}
+				x, err := g.sum(sum, params[0])
+				return Scalar(x), err
+			}}
+		stringToToken["sum"] = Sum
+	}
+	if g.m.Index(sum) != nil && divideType == types.Operator {
+		tokenMap[Mean] = types.KeywordData[Value[T]]{Symbol: "mean", TokenType: types.SingleFunction,
+			Apply: func(params ...Value[T]) (Value[T], error) {
+				if !params[0].IsList {
+					return Value[T]{}, ErrNotList
+				}
+				if len(params[0].List) == 0 {
+					return Value[T]{}, errors.New("mean of an empty list")
+				}
+				total, err := g.sum(sum, params[0])
+				if err != nil {
+					return Value[T]{}, err
+				}
+				count, err := g.fromInt(len(params[0].List))
+				if err != nil {
+					return Value[T]{}, err
+				}
+				x, err := g.m.ApplyKeyword(divide, total, count)
+				return Scalar(x), err
+			}}
+		stringToToken["mean"] = Mean
+	}
+	// filter(v, condition, list) is the list of the elements for which condition holds, with v bound to each,
+	// using the element group's conditional
+	if conditional, ok := g.m.ConditionalToken(); ok {
+		_, keyword := g.m.StringToTokenType(conditional)
+		holds := g.m.Condition(keyword)
+		tokenMap[Filter] = types.KeywordData[Value[T]]{Symbol: "filter", TokenType: types.SingleFunction, Arity: 3, Binds: 1,
+			Lambda: func(body func(...Value[T]) (Value[T], error), params ...Value[T]) (Value[T], error) {
+				if !params[0].IsList {
+					return Value[T]{}, ErrNotList
+				}
+				result := []T{}
+				for _, x := range params[0].List {
+					value, err := body(Scalar(x))
+					if err != nil {
+						return Value[T]{}, err
+					}
+					if value.IsList {
+						return Value[T]{}, errors.New("the condition of filter must be a scalar")
+					}
+					if holds(value.Scalar) {
+						result = append(result, x)
+					}
+				}
+				return Of(result...), nil
+			}}
+		stringToToken["filter"] = Filter
+	}
+
+	g.liftKeywords(tokenMap, stringToToken, precedence)
+	return tokenMap, stringToToken, precedence
+}
+
+// Splits the elements of a list literal, without its brackets, at the commas outside of nested brackets
+func splitElements(s string) []string {
+	elements := []string{}
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				elements = append(elements, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(elements, strings.TrimSpace(s[start:]))
+}
+
+// Parses values of the element group as scalars, and list literals of them, such as "[1, 2.5, -3]"
+func (g group[T]) getValue(s string) (Value[T], bool) {
+	if x, ok := g.m.GetValue(s); ok {
+		return Scalar(x), true
+	}
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return Value[T]{}, false
+	}
+	if strings.TrimSpace(s[1:len(s)-1]) == "" {
+		return Of[T](), true
+	}
+	elements := []T{}
+	for _, element := range splitElements(s[1 : len(s)-1]) {
+		x, ok := g.m.GetValue(element)
+		if !ok {
+			return Value[T]{}, false
+		}
+		elements = append(elements, x)
+	}
+	return Of(elements...), true
+}
+
+// Formats a list like "[1, 2, 3]", with each element formatted by the element group
+func (g group[T]) formatValue(v Value[T]) string {
+	if !v.IsList {
+		return g.m.FormatValue(v.Scalar)
+	}
+	elements := make([]string, len(v.List))
+	for i, x := range v.List {
+		elements[i] = g.m.FormatValue(x)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// New lifts a group into a group of its values and lists of them. The keywords of the group are applied to
+// each element of lists, with scalars used for every element, so [1, 2] * 3 is [3, 6].
+//
+// a..b is the list of integers from a to b, using the group's series to convert the bounds to integers.
+// len, map and reduce are defined for every group. sum(list) and mean(list) need the group's sum series,
+// which they replace, and filter needs its conditional. List literals contain values, not expressions.
+func New[T any](m *types.MathGroup[T]) *types.MathGroup[Value[T]] {
+	g := group[T]{m}
+	tokenMap, stringToToken, precedence := g.tokenMap()
+	return types.NewMathGroup(tokenMap, stringToToken, precedence, g.getValue,
+		types.WithName[Value[T]]("list("+m.Name+")"), types.WithFormat(g.formatValue), types.WithAngleMode[Value[T]](m.AngleMode()))
+}
+
+// Real represents lists of real numbers.
+var Real = New(real.Real)
+
+// NewInterval lifts an interval of the element group into an interval of scalars.
+func NewInterval[T any](interval types.Interval[T]) *types.Interval[Value[T]] {
+	return &types.Interval[Value[T]]{
+		Start: Scalar(interval.Start),
+		Step:  Scalar(interval.Step),
+		End:   Scalar(interval.End),
+		Next: func(cur Value[T]) (Value[T], bool) {
+			next, done := interval.Next(cur.Scalar)
+			return Scalar(next), done
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package list_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/yasteen/go-parse/evaluate"
+	"github.com/yasteen/go-parse/mathgroups/list"
+	"github.com/yasteen/go-parse/mathgroups/rational"
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, input float64, expected string, t *testing.T) {
+	runnableList := run.GetRunnableMathGroup(list.Real)
+	r, err := runnableList.MapValues(expression, *list.NewInterval(*real.NewInterval(input, 1, input)), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := list.Real.FormatValue(r[0]); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("[1, 2.5, -3]", 0, "[1, 2.5, -3]", t)
+	testMapValuesHelper("[]", 0, "[]", t)
+	testMapValuesHelper("1..x", 4, "[1, 2, 3, 4]", t)
+	testMapValuesHelper("x..x-1", 4, "[]", t)
+	testMapValuesHelper("[1, 2] * x + 1", 3, "[4, 7]", t)
+	testMapValuesHelper("[1, 2] * [3, 4] - -[1, 1]", 0, "[4, 9]", t)
+	testMapValuesHelper("abs(x..x+2)", -1, "[1, 0, 1]", t)
+	testMapValuesHelper("x * 2", 1.5, "3", t)
+}
+
+func TestFunctions(t *testing.T) {
+	testMapValuesHelper("len(1..x)", 10, "10", t)
+	testMapValuesHelper("sum(1..x)", 10, "55", t)
+	testMapValuesHelper("mean([1, 2, 3, 4]) * x", 2, "5", t)
+	testMapValuesHelper("map(v, v^2, 1..x)", 4, "[1, 4, 9, 16]", t)
+	testMapValuesHelper("map(x, x * 2, [1, 2]) + x", 3, "[5, 7]", t)
+	testMapValuesHelper("filter(v, v > x, 1..5)", 2, "[3, 4, 5]", t)
+	testMapValuesHelper("reduce(a, v, a * v, 1, 1..x)", 5, "120", t)
+	testMapValuesHelper("reduce(a, v, max(a, v), x, [3, 9, 4])", 0, "9", t)
+	testMapValuesHelper("sum(map(v, v * x, filter(v, mod(v, 2) == 0, 1..10)))", 2, "60", t)
+	testMapValuesHelper("if(len(1..x) > 2, 1..x, 0)", 3, "[1, 2, 3]", t)
+}
+
+func TestErrors(t *testing.T) {
+	runnableList := run.GetRunnableMathGroup(list.Real)
+	for _, expression := range []string{
+		"[1, 2] + [1, 2, 3]", "sum(x)", "len(x)", "mean([])", "1..x/2", "map(v, [v], [1])",
+		"map(1, v, [1])", "map(v, v, [1]) + v", "1..1e9", "if([1, 5] > 2, 1, 0)", "{1 if 1..x > 2; 0 otherwise}",
+	} {
+		if _, err := runnableList.MapValues(expression, *list.NewInterval(*real.NewInterval(3, 1, 3)), "x"); err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+
+	// The tree evaluator checks conditions too, such as within the body of map
+	parsed, err := parsexp.Parse("map(v, if(v..5 > 2, 1, 0), [1])", "x", list.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := evaluate.Once(parsed, list.Scalar(3.0), list.Real); err == nil {
+		t.Error("Expected an error on a list condition")
+	}
+}
+
+func TestElementGroup(t *testing.T) {
+	rationals := list.New(rational.Rational)
+	parsed, err := parsexp.Parse("mean(map(v, 1/v, 1..3))", "x", rationals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expression := &parsexp.Expression[list.Value[*big.Rat]]{Postfix: parsed, Variables: []string{"x"}, Group: rationals}
+	data, err := json.Marshal(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &parsexp.Expression[list.Value[*big.Rat]]{Group: rationals}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	value, err := evaluate.Once(decoded.Postfix, list.Scalar(new(big.Rat)), rationals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := rationals.FormatValue(value); output != "11/18" {
+		t.Error("Failed on rational mean - Expected: 11/18 Got:", output)
+	}
+}
@@ -0,0 +1,412 @@
+// Package matrix is an implementation of dense float64 matrices with element-wise and linear-algebra operators.
+package matrix
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Dense is a matrix of float64, stored in row-major order. Scalars are 1 × 1 matrices.
+type Dense struct {
+	Rows int
+	Cols int
+	Data []float64
+}
+
+// Common functions and operations defined for the matrix group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	ElementMultiply
+	ElementDivide
+	Det
+	Inv
+	Transpose
+	Trace
+)
+
+// ErrSingular is returned when inverting a singular matrix.
+var ErrSingular = errors.New("matrix is singular")
+
+// New constructs a matrix from its rows, which must all have the same length.
+func New(rows ...[]float64) Dense {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		panic("Matrix must not be empty")
+	}
+	m := Dense{Rows: len(rows), Cols: len(rows[0])}
+	for _, row := range rows {
+		if len(row) != m.Cols {
+			panic("Rows must have the same length")
+		}
+		m.Data = append(m.Data, row...)
+	}
+	return m
+}
+
+// Scalar constructs a 1 × 1 matrix.
+func Scalar(x float64) Dense {
+	return Dense{Rows: 1, Cols: 1, Data: []float64{x}}
+}
+
+// Identity constructs the n × n identity matrix.
+func Identity(n int) Dense {
+	m := zeros(n, n)
+	for i := 0; i < n; i++ {
+		m.Data[i*n+i] = 1
+	}
+	return m
+}
+
+func zeros(rows int, cols int) Dense {
+	return Dense{Rows: rows, Cols: cols, Data: make([]float64, rows*cols)}
+}
+
+// At returns the element at the given row and column.
+func (m Dense) At(row int, col int) float64 {
+	return m.Data[row*m.Cols+col]
+}
+
+// IsScalar returns true if the matrix is 1 × 1.
+func (m Dense) IsScalar() bool {
+	return m.Rows == 1 && m.Cols == 1
+}
+
+func (m Dense) shape() string {
+	return strconv.Itoa(m.Rows) + "×" + strconv.Itoa(m.Cols)
+}
+
+func shapeError(operation string, a Dense, b Dense) error {
+	return errors.New("cannot " + operation + " matrices of shapes " + a.shape() + " and " + b.shape())
+}
+
+// Applies f to corresponding elements. A scalar operand is broadcast to the shape of the other.
+func elementwise(operation string, a Dense, b Dense, f func(float64, float64) float64) (Dense, error) {
+	rows, cols := a.Rows, a.Cols
+	switch {
+	case a.IsScalar():
+		rows, cols = b.Rows, b.Cols
+	case b.IsScalar():
+	case a.Rows != b.Rows || a.Cols != b.Cols:
+		return Dense{}, shapeError(operation, a, b)
+	}
+	result := zeros(rows, cols)
+	for i := range result.Data {
+		x, y := a.Data[0], b.Data[0]
+		if !a.IsScalar() {
+			x = a.Data[i]
+		}
+		if !b.IsScalar() {
+			y = b.Data[i]
+		}
+		result.Data[i] = f(x, y)
+	}
+	return result, nil
+}
+
+// Matrix product, or scalar multiplication if either operand is a scalar
+func multiply(a Dense, b Dense) (Dense, error) {
+	if a.IsScalar() || b.IsScalar() {
+		return elementwise("multiply", a, b, func(x, y float64) float64 { return x * y })
+	}
+	if a.Cols != b.Rows {
+		return Dense{}, shapeError("multiply", a, b)
+	}
+	result := zeros(a.Rows, b.Cols)
+	for i := 0; i < a.Rows; i++ {
+		for k := 0; k < a.Cols; k++ {
+			for j := 0; j < b.Cols; j++ {
+				result.Data[i*b.Cols+j] += a.At(i, k) * b.At(k, j)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Reduces a copy of m to row echelon form with partial pivoting, applying the same row operations to
+// a copy of other if it is not empty. Returns the reduced matrices, and the determinant of m.
+func eliminate(m Dense, other Dense) (Dense, Dense, float64) {
+	a := Dense{Rows: m.Rows, Cols: m.Cols, Data: append([]float64{}, m.Data...)}
+	b := Dense{Rows: other.Rows, Cols: other.Cols, Data: append([]float64{}, other.Data...)}
+	swapRows := func(d Dense, i int, j int) {
+		for c := 0; c < d.Cols; c++ {
+			d.Data[i*d.Cols+c], d.Data[j*d.Cols+c] = d.Data[j*d.Cols+c], d.Data[i*d.Cols+c]
+		}
+	}
+	addRow := func(d Dense, target int, source int, factor float64) {
+		for c := 0; c < d.Cols; c++ {
+			d.Data[target*d.Cols+c] += factor * d.Data[source*d.Cols+c]
+		}
+	}
+
+	det := 1.0
+	n := a.Rows
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a.At(row, col)) > math.Abs(a.At(pivot, col)) {
+				pivot = row
+			}
+		}
+		if a.At(pivot, col) == 0 {
+			return a, b, 0
+		}
+		if pivot != col {
+			swapRows(a, pivot, col)
+			swapRows(b, pivot, col)
+			det = -det
+		}
+		det *= a.At(col, col)
+		for row := col + 1; row < n; row++ {
+			factor := -a.At(row, col) / a.At(col, col)
+			addRow(a, row, col, factor)
+			addRow(b, row, col, factor)
+		}
+	}
+	return a, b, det
+}
+
+func determinant(m Dense) (float64, error) {
+	if m.Rows != m.Cols {
+		return 0, errors.New("determinant of a non-square matrix of shape " + m.shape())
+	}
+	_, _, det := eliminate(m, Dense{})
+	return det, nil
+}
+
+func inverse(m Dense) (Dense, error) {
+	if m.Rows != m.Cols {
+		return Dense{}, errors.New("inverse of a non-square matrix of shape " + m.shape())
+	}
+	a, b, det := eliminate(m, Identity(m.Rows))
+	if det == 0 {
+		return Dense{}, ErrSingular
+	}
+	// Back substitution, leaving the identity in a and the inverse in b
+	n := m.Rows
+	for col := n - 1; col >= 0; col-- {
+		pivot := a.At(col, col)
+		for c := 0; c < n; c++ {
+			b.Data[col*n+c] /= pivot
+		}
+		for row := 0; row < col; row++ {
+			factor := a.At(row, col)
+			for c := 0; c < n; c++ {
+				b.Data[row*n+c] -= factor * b.At(col, c)
+			}
+		}
+	}
+	return b, nil
+}
+
+// Raises a matrix to an integer power by repeated squaring. A negative power raises the inverse.
+func power(m Dense, exponent Dense) (Dense, error) {
+	if !exponent.IsScalar() {
+		return Dense{}, errors.New("exponent must be a scalar")
+	}
+	e := exponent.Data[0]
+	if m.IsScalar() {
+		return Scalar(math.Pow(m.Data[0], e)), nil
+	}
+	if m.Rows != m.Cols {
+		return Dense{}, errors.New("power of a non-square matrix of shape " + m.shape())
+	}
+	if e != math.Trunc(e) || math.Abs(e) > math.MaxInt32 {
+		return Dense{}, errors.New("power of a matrix must have an integer exponent")
+	}
+	if e < 0 {
+		inv, err := inverse(m)
+		if err != nil {
+			return Dense{}, err
+		}
+		m, e = inv, -e
+	}
+	result := Identity(m.Rows)
+	for n := int(e); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result, _ = multiply(result, m)
+		}
+		m, _ = multiply(m, m)
+	}
+	return result, nil
+}
+
+var matrixTokenMap = map[types.Keyword]types.KeywordData[Dense]{
+	Add: {Symbol: "+", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return elementwise("add", params[0], params[1], func(x, y float64) float64 { return x + y })
+		}},
+	Subtract: {Symbol: "-", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return elementwise("subtract", params[0], params[1], func(x, y float64) float64 { return x - y })
+		}},
+	Multiply: {Symbol: "*", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return multiply(params[0], params[1])
+		}},
+	Divide: {Symbol: "/", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			if params[1].IsScalar() {
+				if params[1].Data[0] == 0 {
+					return Dense{}, types.ErrDivisionByZero
+				}
+				return elementwise("divide", params[0], params[1], func(x, y float64) float64 { return x / y })
+			}
+			inv, err := inverse(params[1])
+			if err != nil {
+				return Dense{}, err
+			}
+			return multiply(params[0], inv)
+		}},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return power(params[0], params[1])
+		}},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Dense) (Dense, error) {
+			return elementwise("negate", Scalar(-1), params[0], func(x, y float64) float64 { return x * y })
+		}},
+	ElementMultiply: {Symbol: ".*", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return elementwise("multiply", params[0], params[1], func(x, y float64) float64 { return x * y })
+		}},
+	ElementDivide: {Symbol: "./", TokenType: types.Operator,
+		Apply: func(params ...Dense) (Dense, error) {
+			return elementwise("divide", params[0], params[1], func(x, y float64) float64 { return x / y })
+		}},
+	Det: {Symbol: "det", TokenType: types.SingleFunction,
+		Apply: func(params ...Dense) (Dense, error) {
+			det, err := determinant(params[0])
+			return Scalar(det), err
+		}},
+	Inv: {Symbol: "inv", TokenType: types.SingleFunction,
+		Apply: func(params ...Dense) (Dense, error) {
+			return inverse(params[0])
+		}},
+	Transpose: {Symbol: "transpose", TokenType: types.SingleFunction,
+		Apply: func(params ...Dense) (Dense, error) {
+			m := params[0]
+			result := zeros(m.Cols, m.Rows)
+			for i := 0; i < m.Rows; i++ {
+				for j := 0; j < m.Cols; j++ {
+					result.Data[j*m.Rows+i] = m.At(i, j)
+				}
+			}
+			return result, nil
+		}},
+	Trace: {Symbol: "trace", TokenType: types.SingleFunction,
+		Apply: func(params ...Dense) (Dense, error) {
+			m := params[0]
+			if m.Rows != m.Cols {
+				return Dense{}, errors.New("trace of a non-square matrix of shape " + m.shape())
+			}
+			trace := 0.0
+			for i := 0; i < m.Rows; i++ {
+				trace += m.At(i, i)
+			}
+			return Scalar(trace), nil
+		}},
+}
+
+var matrixStringToToken = map[string]types.Keyword{
+	"+":         Add,
+	"-":         Subtract,
+	"*":         Multiply,
+	"/":         Divide,
+	"^":         Power,
+	"neg":       Negate,
+	".*":        ElementMultiply,
+	"./":        ElementDivide,
+	"det":       Det,
+	"inv":       Inv,
+	"transpose": Transpose,
+	"trace":     Trace,
+}
+
+var matrixOperatorPrecedence = map[types.Keyword]int{
+	Add:             1,
+	Subtract:        1,
+	Multiply:        2,
+	Divide:          2,
+	ElementMultiply: 2,
+	ElementDivide:   2,
+	Negate:          2,
+	Power:           3,
+}
+
+// Parses a number as a scalar, or a matrix literal such as "[1, 2; 3, 4]",
+// with rows separated by semicolons and elements separated by commas.
+func getMatrix(s string) (Dense, bool) {
+	if !strings.HasPrefix(s, "[") {
+		x, err := strconv.ParseFloat(s, 64)
+		return Scalar(x), err == nil
+	}
+	if !strings.HasSuffix(s, "]") {
+		return Dense{}, false
+	}
+	rows := [][]float64{}
+	for _, rowString := range strings.Split(s[1:len(s)-1], ";") {
+		row := []float64{}
+		for _, element := range strings.Split(rowString, ",") {
+			x, err := strconv.ParseFloat(strings.TrimSpace(element), 64)
+			if err != nil {
+				return Dense{}, false
+			}
+			row = append(row, x)
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return Dense{}, false
+		}
+		rows = append(rows, row)
+	}
+	return New(rows...), true
+}
+
+// Formats a matrix as a literal, or a scalar as a number
+func formatMatrix(m Dense) string {
+	format := func(x float64) string {
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	if m.IsScalar() {
+		return format(m.Data[0])
+	}
+	rows := make([]string, m.Rows)
+	for i := range rows {
+		elements := make([]string, m.Cols)
+		for j := range elements {
+			elements[j] = format(m.At(i, j))
+		}
+		rows[i] = strings.Join(elements, ", ")
+	}
+	return "[" + strings.Join(rows, "; ") + "]"
+}
+
+// Matrix represents dense float64 matrices and some defined operations/functions.
+// * is the matrix product, while .* and ./ are element-wise. Scalars are broadcast in element-wise operations.
+var Matrix = types.NewMathGroup(matrixTokenMap, matrixStringToToken, matrixOperatorPrecedence, getMatrix, types.WithName[Dense]("matrix"), types.WithFormat(formatMatrix))
+
+// NewScalarInterval constructs a new interval of scalars.
+func NewScalarInterval(start float64, step float64, end float64) *types.Interval[Dense] {
+	if step <= 0 || start > end {
+		panic("Invalid interval")
+	}
+	return &types.Interval[Dense]{
+		Start: Scalar(start),
+		Step:  Scalar(step),
+		End:   Scalar(end),
+		Next: func(cur Dense) (Dense, bool) {
+			next := cur.Data[0] + step
+			if next > end {
+				return Scalar(end), true
+			}
+			return Scalar(next), false
+		},
+	}
+}
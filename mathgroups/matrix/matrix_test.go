@@ -0,0 +1,62 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/matrix"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, input float64, expected string, t *testing.T) {
+	runnableMatrix := run.GetRunnableMathGroup(matrix.Matrix)
+	r, err := runnableMatrix.MapValues(expression, *matrix.NewScalarInterval(input, 1, input), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := matrix.Matrix.FormatValue(r[0]); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func testMapValuesErrorHelper(expression string, t *testing.T) {
+	runnableMatrix := run.GetRunnableMathGroup(matrix.Matrix)
+	if _, err := runnableMatrix.MapValues(expression, *matrix.NewScalarInterval(0, 1, 0), "x"); err == nil {
+		t.Error("Expected an error on expression", expression)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("[1, 2; 3, 4] + [1, 1; 1, 1]", 0, "[2, 3; 4, 5]", t)
+	testMapValuesHelper("[1, 2; 3, 4] * [5; 6]", 0, "[17; 39]", t)
+	testMapValuesHelper("x * [1, 2; 3, 4] - 1", 2, "[1, 3; 5, 7]", t)
+	testMapValuesHelper("[1, 2; 3, 4] .* [1, 2; 3, 4]", 0, "[1, 4; 9, 16]", t)
+	testMapValuesHelper("[2, 4] ./ [2, 1]", 0, "[1, 4]", t)
+	testMapValuesHelper("-[1, -2]", 0, "[-1, 2]", t)
+	testMapValuesHelper("[1, 1; 1, 0]^10", 0, "[89, 55; 55, 34]", t)
+	testMapValuesHelper("transpose([1, 2, 3])", 0, "[1; 2; 3]", t)
+	testMapValuesHelper("[1, 2, 3] * transpose([1, 2, 3])", 0, "14", t)
+	testMapValuesHelper("trace([1, 2; 3, 4]) + x^2", 3, "14", t)
+}
+
+func TestLinearAlgebra(t *testing.T) {
+	testMapValuesHelper("det([1, 2; 3, 4])", 0, "-2", t)
+	testMapValuesHelper("det([0, 1, 0; 1, 0, 0; 0, 0, 1])", 0, "-1", t)
+	testMapValuesHelper("inv([2, 1; 1, 1])", 0, "[1, -1; -1, 2]", t)
+	testMapValuesHelper("[1, 2; 0, 1] * inv([1, 2; 0, 1])", 0, "[1, 0; 0, 1]", t)
+	testMapValuesHelper("[2, 0; 0, 4]^-1", 0, "[0.5, 0; 0, 0.25]", t)
+	testMapValuesHelper("[1, 2] / [2, 0; 0, 4]", 0, "[0.5, 0.5]", t)
+}
+
+func TestShapeErrors(t *testing.T) {
+	testMapValuesErrorHelper("[1, 2] + [1, 2, 3]", t)
+	testMapValuesErrorHelper("[1, 2] * [1, 2]", t)
+	testMapValuesErrorHelper("det([1, 2])", t)
+	testMapValuesErrorHelper("inv([1, 2; 2, 4])", t)
+	testMapValuesErrorHelper("trace([1, 2])", t)
+	testMapValuesErrorHelper("[1, 2; 3, 4]^0.5", t)
+	testMapValuesErrorHelper("[1, 2] / x", t)
+	testMapValuesErrorHelper("[1, 2; 3] + 1", t)
+}
@@ -0,0 +1,161 @@
+// Package modular is an implementation of integers modulo n (Z/nZ) with common operators and functions.
+package modular
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Common functions and operations defined for modular groups
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Inverse
+	GCD
+)
+
+// A modular group, identified by its modulus
+type modulus struct {
+	n *big.Int
+}
+
+// Reduces x into the range [0, n)
+func (m modulus) reduce(x *big.Int) *big.Int {
+	return x.Mod(x, m.n)
+}
+
+func (m modulus) inverse(x *big.Int) (*big.Int, error) {
+	inverse := new(big.Int).ModInverse(new(big.Int).Mod(x, m.n), m.n)
+	if inverse == nil {
+		return nil, errors.New(x.String() + " has no inverse modulo " + m.n.String())
+	}
+	return inverse, nil
+}
+
+// Raises x to the given power by repeated squaring. A negative exponent raises the inverse of x.
+func (m modulus) pow(x *big.Int, exponent *big.Int) (*big.Int, error) {
+	if exponent.Sign() >= 0 {
+		return new(big.Int).Exp(x, exponent, m.n), nil
+	}
+	inverse, err := m.inverse(x)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Exp(inverse, new(big.Int).Neg(exponent), m.n), nil
+}
+
+func (m modulus) tokenMap() map[types.Keyword]types.KeywordData[*big.Int] {
+	return map[types.Keyword]types.KeywordData[*big.Int]{
+		Add: {Symbol: "+", TokenType: types.Operator,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return m.reduce(new(big.Int).Add(params[0], params[1])), nil
+			}},
+		Subtract: {Symbol: "-", TokenType: types.Operator,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return m.reduce(new(big.Int).Sub(params[0], params[1])), nil
+			}},
+		Multiply: {Symbol: "*", TokenType: types.Operator,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return m.reduce(new(big.Int).Mul(params[0], params[1])), nil
+			}},
+		Divide: {Symbol: "/", TokenType: types.Operator,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				inverse, err := m.inverse(params[1])
+				if err != nil {
+					return nil, err
+				}
+				return m.reduce(inverse.Mul(params[0], inverse)), nil
+			}},
+		Power: {Symbol: "^", TokenType: types.Operator,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return m.pow(params[0], params[1])
+			}},
+		// Negation is not reduced, so that negative exponents like x^-1 keep their meaning.
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return new(big.Int).Neg(params[0]), nil
+			}},
+		Inverse: {Symbol: "inv", TokenType: types.SingleFunction,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				return m.inverse(params[0])
+			}},
+		GCD: {Symbol: "gcd", TokenType: types.SingleFunction, Arity: 2,
+			Apply: func(params ...*big.Int) (*big.Int, error) {
+				a := new(big.Int).Abs(params[0])
+				b := new(big.Int).Abs(params[1])
+				return new(big.Int).GCD(nil, nil, a, b), nil
+			}},
+	}
+}
+
+var modularStringToToken = map[string]types.Keyword{
+	"+":   Add,
+	"-":   Subtract,
+	"*":   Multiply,
+	"/":   Divide,
+	"^":   Power,
+	"neg": Negate,
+	"inv": Inverse,
+	"gcd": GCD,
+}
+
+var modularOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses integer literals, such as "65537" or "0x1f". Literals are not reduced,
+// so that they can be used as exponents larger than the modulus.
+func getInteger(s string) (*big.Int, bool) {
+	return new(big.Int).SetString(s, 0)
+}
+
+// Formats a value as its representative in [0, n)
+func (m modulus) formatValue(x *big.Int) string {
+	return new(big.Int).Mod(x, m.n).String()
+}
+
+// New constructs the group of integers modulo n. Values are integers representing their residue,
+// and are formatted as their representative in [0, n). Every operation other than negation
+// reduces its result into [0, n). The exponent of ^ is used as an integer, and is not reduced.
+func New(n *big.Int) *types.MathGroup[*big.Int] {
+	if n.Cmp(big.NewInt(2)) < 0 {
+		panic("Modulus must be at least 2")
+	}
+	m := modulus{n: new(big.Int).Set(n)}
+	return types.NewMathGroup(m.tokenMap(), modularStringToToken, modularOperatorPrecedence, getInteger, types.WithName[*big.Int]("Z/"+n.String()+"Z"), types.WithFormat(m.formatValue))
+}
+
+// NewInt constructs the group of integers modulo n.
+func NewInt(n int64) *types.MathGroup[*big.Int] {
+	return New(big.NewInt(n))
+}
+
+// NewInterval constructs a new interval of integers.
+func NewInterval(start *big.Int, step *big.Int, end *big.Int) *types.Interval[*big.Int] {
+	if step.Sign() <= 0 || start.Cmp(end) > 0 {
+		panic("Invalid interval")
+	}
+	return &types.Interval[*big.Int]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur *big.Int) (*big.Int, bool) {
+			next := new(big.Int).Add(cur, step)
+			if next.Cmp(end) > 0 {
+				return end, true
+			}
+			return next, false
+		},
+	}
+}
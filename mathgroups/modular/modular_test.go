@@ -0,0 +1,47 @@
+package modular_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/modular"
+	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
+)
+
+func testMapValuesHelper(group *types.MathGroup[*big.Int], expression string, input int64, expected int64, t *testing.T) {
+	x := big.NewInt(input)
+	runnableModular := run.GetRunnableMathGroup(group)
+	values, err := runnableModular.MapValues(expression, *modular.NewInterval(x, big.NewInt(1), x), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if group.FormatValue(values[0]) != big.NewInt(expected).String() {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", values[0])
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	z7 := modular.NewInt(7)
+	testMapValuesHelper(z7, "x + 5", 4, 2, t)
+	testMapValuesHelper(z7, "-x", 3, 4, t)
+	testMapValuesHelper(z7, "3 / x", 5, 2, t)
+	testMapValuesHelper(z7, "inv(x) + x^-1", 3, 3, t)
+	testMapValuesHelper(z7, "x ^ 8", 3, 2, t)
+	testMapValuesHelper(modular.NewInt(100), "gcd(x, 18)", 12, 6, t)
+
+	n := big.NewInt(3233)
+	expected := new(big.Int).Exp(big.NewInt(2*65+7), big.NewInt(65537), n)
+	testMapValuesHelper(modular.New(n), "(2*x + 7) ^ 65537", 65, expected.Int64(), t)
+}
+
+func TestNoInverse(t *testing.T) {
+	z8 := run.GetRunnableMathGroup(modular.NewInt(8))
+	x := big.NewInt(4)
+	if _, err := z8.MapValues("2 / x", *modular.NewInterval(x, big.NewInt(1), x), "x"); err == nil {
+		t.Error("Failed to detect a value with no inverse.")
+	}
+}
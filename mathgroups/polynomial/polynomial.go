@@ -0,0 +1,369 @@
+// Package polynomial is an implementation of univariate polynomials with exact rational coefficients,
+// and common operators and functions.
+package polynomial
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Poly is a polynomial in x, given by its coefficients from the constant term upwards.
+// The coefficient of the highest power is never zero, so the zero polynomial has no coefficients.
+type Poly []*big.Rat
+
+// Common functions and operations defined for the polynomial group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Quotient
+	Remainder
+	Power
+	Negate
+	GCD
+	Deriv
+	At
+	Sum
+	Prod
+)
+
+// ErrOverflow is returned when a power would be too large to compute.
+var ErrOverflow = errors.New("polynomial overflow")
+
+// The highest degree of a power, and the most bits in the numerator or denominator of each of its coefficients
+const (
+	maxDegree          = 1 << 12
+	maxCoefficientBits = 1 << 24
+)
+
+// X is the polynomial x.
+var X = Poly{new(big.Rat), big.NewRat(1, 1)}
+
+// Constant returns the constant polynomial c.
+func Constant(c *big.Rat) Poly {
+	return normalize(Poly{new(big.Rat).Set(c)})
+}
+
+// Removes zero coefficients of the highest powers
+func normalize(p Poly) Poly {
+	for len(p) > 0 && p[len(p)-1].Sign() == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// Degree returns the degree of the polynomial, or -1 for the zero polynomial.
+func (p Poly) Degree() int {
+	return len(p) - 1
+}
+
+// Eval evaluates the polynomial at x.
+func (p Poly) Eval(x *big.Rat) *big.Rat {
+	result := new(big.Rat)
+	for i := len(p) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, p[i])
+	}
+	return result
+}
+
+// String formats a polynomial with its highest power first, such as "x^2 - 1/2*x + 3".
+func (p Poly) String() string {
+	if len(p) == 0 {
+		return "0"
+	}
+	var s strings.Builder
+	for i := len(p) - 1; i >= 0; i-- {
+		c := p[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		switch {
+		case s.Len() == 0 && c.Sign() < 0:
+			s.WriteString("-")
+		case s.Len() > 0 && c.Sign() < 0:
+			s.WriteString(" - ")
+		case s.Len() > 0:
+			s.WriteString(" + ")
+		}
+		abs := new(big.Rat).Abs(c)
+		if i == 0 || abs.Cmp(big.NewRat(1, 1)) != 0 {
+			s.WriteString(abs.RatString())
+			if i > 0 {
+				s.WriteString("*")
+			}
+		}
+		if i == 1 {
+			s.WriteString("x")
+		} else if i > 1 {
+			s.WriteString("x^" + strconv.Itoa(i))
+		}
+	}
+	return s.String()
+}
+
+func add(p Poly, q Poly, sign int) Poly {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	result := make(Poly, n)
+	for i := range result {
+		result[i] = new(big.Rat)
+		if i < len(p) {
+			result[i].Set(p[i])
+		}
+		if i < len(q) {
+			if sign < 0 {
+				result[i].Sub(result[i], q[i])
+			} else {
+				result[i].Add(result[i], q[i])
+			}
+		}
+	}
+	return normalize(result)
+}
+
+func multiply(p Poly, q Poly) Poly {
+	if len(p) == 0 || len(q) == 0 {
+		return Poly{}
+	}
+	result := make(Poly, len(p)+len(q)-1)
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+	term := new(big.Rat)
+	for i, a := range p {
+		for j, b := range q {
+			result[i+j].Add(result[i+j], term.Mul(a, b))
+		}
+	}
+	return normalize(result)
+}
+
+// Divides p by q with polynomial long division, giving the quotient and remainder
+func divide(p Poly, q Poly) (Poly, Poly, error) {
+	if len(q) == 0 {
+		return nil, nil, types.ErrDivisionByZero
+	}
+	if len(p) < len(q) {
+		return Poly{}, p, nil
+	}
+	remainder := add(p, Poly{}, 1)
+	quotient := make(Poly, len(p)-len(q)+1)
+	lead := q[len(q)-1]
+	for i := len(quotient) - 1; i >= 0; i-- {
+		quotient[i] = new(big.Rat).Quo(remainder[i+len(q)-1], lead)
+		for j, c := range q {
+			remainder[i+j].Sub(remainder[i+j], new(big.Rat).Mul(quotient[i], c))
+		}
+	}
+	return normalize(quotient), normalize(remainder[:len(q)-1]), nil
+}
+
+// Returns the monic greatest common divisor of p and q, using the Euclidean algorithm
+func gcd(p Poly, q Poly) Poly {
+	for len(q) > 0 {
+		_, remainder, _ := divide(p, q)
+		p, q = q, remainder
+	}
+	if len(p) == 0 {
+		return p
+	}
+	lead := new(big.Rat).Inv(p[len(p)-1])
+	return multiply(p, Poly{lead})
+}
+
+func deriv(p Poly) Poly {
+	if len(p) == 0 {
+		return p
+	}
+	result := make(Poly, len(p)-1)
+	for i := range result {
+		result[i] = new(big.Rat).Mul(p[i+1], big.NewRat(int64(i+1), 1))
+	}
+	return normalize(result)
+}
+
+// Substitutes q for x in p, with Horner's method
+func compose(p Poly, q Poly) Poly {
+	result := Poly{}
+	for i := len(p) - 1; i >= 0; i-- {
+		result = add(multiply(result, q), Poly{p[i]}, 1)
+	}
+	return result
+}
+
+// Raises p to a non-negative integer power, given as a constant polynomial.
+// Powers above maxDegree, or with coefficients above maxCoefficientBits, are reported with ErrOverflow.
+func pow(p Poly, exponent Poly) (Poly, error) {
+	if exponent.Degree() > 0 {
+		return nil, errors.New("exponent must be a constant")
+	}
+	n := big.NewInt(0)
+	if len(exponent) > 0 {
+		if !exponent[0].IsInt() || exponent[0].Sign() < 0 || !exponent[0].Num().IsInt64() {
+			return nil, errors.New("exponent must be a non-negative integer")
+		}
+		n = exponent[0].Num()
+	}
+	if p.Degree() > 0 && n.Cmp(big.NewInt(int64(maxDegree/p.Degree()))) > 0 {
+		return nil, ErrOverflow
+	}
+	bits := 0
+	for _, c := range p {
+		if c.Num().BitLen() > bits {
+			bits = c.Num().BitLen()
+		}
+		if c.Denom().BitLen() > bits {
+			bits = c.Denom().BitLen()
+		}
+	}
+	// Powers of 0, 1 and -1 stay small
+	if bits > 1 && new(big.Int).Mul(n, big.NewInt(int64(bits))).Cmp(big.NewInt(maxCoefficientBits)) > 0 {
+		return nil, ErrOverflow
+	}
+	result := Poly{big.NewRat(1, 1)}
+	square := p
+	for e := n.Int64(); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = multiply(result, square)
+		}
+		if e > 1 {
+			square = multiply(square, square)
+		}
+	}
+	return result, nil
+}
+
+// Converts an integral constant to an int, for the bounds of a series
+func toInt(p Poly) (int, bool) {
+	if len(p) == 0 {
+		return 0, true
+	}
+	if p.Degree() > 0 || !p[0].IsInt() || p[0].Num().CmpAbs(big.NewInt(math.MaxInt32)) > 0 {
+		return 0, false
+	}
+	return int(p[0].Num().Int64()), true
+}
+
+var polynomialTokenMap = map[types.Keyword]types.KeywordData[Poly]{
+	Add: {Symbol: "+", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			return add(params[0], params[1], 1), nil
+		}},
+	Subtract: {Symbol: "-", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			return add(params[0], params[1], -1), nil
+		}},
+	Multiply: {Symbol: "*", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			return multiply(params[0], params[1]), nil
+		}},
+	Quotient: {Symbol: "/", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			quotient, _, err := divide(params[0], params[1])
+			return quotient, err
+		}},
+	Remainder: {Symbol: "%", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			_, remainder, err := divide(params[0], params[1])
+			return remainder, err
+		}},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...Poly) (Poly, error) {
+			return pow(params[0], params[1])
+		}},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Poly) (Poly, error) {
+			return add(Poly{}, params[0], -1), nil
+		}},
+	GCD: {Symbol: "gcd", TokenType: types.SingleFunction, Arity: 2,
+		Apply: func(params ...Poly) (Poly, error) {
+			return gcd(params[0], params[1]), nil
+		}},
+	Deriv: {Symbol: "deriv", TokenType: types.SingleFunction,
+		Apply: func(params ...Poly) (Poly, error) {
+			return deriv(params[0]), nil
+		}},
+	// at(p, c) evaluates p at c, or substitutes c for x if c is not a constant
+	At: {Symbol: "at", TokenType: types.SingleFunction, Arity: 2,
+		Apply: func(params ...Poly) (Poly, error) {
+			return compose(params[0], params[1]), nil
+		}},
+	Sum: types.Series("sum", Poly{}, func(p Poly, q Poly) (Poly, error) {
+		return add(p, q, 1), nil
+	}, toInt),
+	Prod: types.Series("prod", Poly{big.NewRat(1, 1)}, func(p Poly, q Poly) (Poly, error) {
+		return multiply(p, q), nil
+	}, toInt),
+}
+
+var polynomialStringToToken = map[string]types.Keyword{
+	"+":     Add,
+	"-":     Subtract,
+	"*":     Multiply,
+	"/":     Quotient,
+	"%":     Remainder,
+	"^":     Power,
+	"neg":   Negate,
+	"gcd":   GCD,
+	"deriv": Deriv,
+	"at":    At,
+	"sum":   Sum,
+	"prod":  Prod,
+}
+
+var polynomialOperatorPrecedence = map[types.Keyword]int{
+	Add:       1,
+	Subtract:  1,
+	Multiply:  2,
+	Quotient:  2,
+	Remainder: 2,
+	Negate:    2,
+	Power:     3,
+}
+
+// Parses the indeterminate "x", and constants written as integers or decimals
+func getPolynomial(s string) (Poly, bool) {
+	if s == "x" {
+		return X, true
+	}
+	if strings.Contains(s, "/") {
+		return nil, false
+	}
+	c, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, false
+	}
+	return Constant(c), true
+}
+
+// Polynomial represents polynomials in x with rational coefficients, and some defined operations/functions.
+// Expressions are expanded, so (x+1)^2 evaluates to x^2 + 2*x + 1.
+// / and % give the quotient and remainder of polynomial long division. Values are never modified in place.
+var Polynomial = types.NewMathGroup(polynomialTokenMap, polynomialStringToToken, polynomialOperatorPrecedence, getPolynomial, types.WithName[Poly]("polynomial"), types.WithFormat(Poly.String))
+
+// NewInterval constructs a new interval of constant polynomials.
+func NewInterval(start *big.Rat, step *big.Rat, end *big.Rat) *types.Interval[Poly] {
+	if step.Sign() <= 0 || start.Cmp(end) > 0 {
+		panic("Invalid interval")
+	}
+	return &types.Interval[Poly]{
+		Start: Constant(start),
+		Step:  Constant(step),
+		End:   Constant(end),
+		Next: func(cur Poly) (Poly, bool) {
+			next := new(big.Rat).Add(cur.Eval(new(big.Rat)), step)
+			if next.Cmp(end) > 0 {
+				return Constant(end), true
+			}
+			return Constant(next), false
+		},
+	}
+}
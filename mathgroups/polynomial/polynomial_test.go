@@ -0,0 +1,75 @@
+package polynomial_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/polynomial"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, input int64, expected string, t *testing.T) {
+	runnablePolynomial := run.GetRunnableMathGroup(polynomial.Polynomial)
+	r, err := runnablePolynomial.MapValues(expression, *polynomial.NewInterval(big.NewRat(input, 1), big.NewRat(1, 1), big.NewRat(input, 1)), "t")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := r[0].String(); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("(x+1)^3 - x", 0, "x^3 + 3*x^2 + 2*x + 1", t)
+	testMapValuesHelper("(x - t)*(x + t)", 2, "x^2 - 4", t)
+	testMapValuesHelper("-(0.5*x - 1)^2", 0, "-1/4*x^2 + x - 1", t)
+	testMapValuesHelper("x - x", 0, "0", t)
+	testMapValuesHelper("(x^2 - 1)^0", 0, "1", t)
+}
+
+func TestDivision(t *testing.T) {
+	testMapValuesHelper("(x^3 - 1) / (x - 1)", 0, "x^2 + x + 1", t)
+	testMapValuesHelper("(x^3 + 2*x + 5) % (x^2 + 1)", 0, "x + 5", t)
+	testMapValuesHelper("(x^3 + 2*x + 5) / (x^2 + 1)", 0, "x", t)
+	testMapValuesHelper("x / 2", 0, "1/2*x", t)
+	testMapValuesHelper("1 / x", 0, "0", t)
+}
+
+func TestFunctions(t *testing.T) {
+	testMapValuesHelper("gcd(x^2 - 1, 2*x^2 + 4*x + 2)", 0, "x + 1", t)
+	testMapValuesHelper("gcd(x^2 + 1, x - 1)", 0, "1", t)
+	testMapValuesHelper("deriv(x^3 - 2*x + 7)", 0, "3*x^2 - 2", t)
+	testMapValuesHelper("at(x^2 + x, t)", 3, "12", t)
+	testMapValuesHelper("at(x^2, x + 1)", 0, "x^2 + 2*x + 1", t)
+}
+
+func TestErrors(t *testing.T) {
+	runnablePolynomial := run.GetRunnableMathGroup(polynomial.Polynomial)
+	zero := big.NewRat(0, 1)
+	for _, expression := range []string{"x / 0", "x % (x - x)", "x^x", "x^-1", "x^0.5"} {
+		if _, err := runnablePolynomial.MapValues(expression, *polynomial.NewInterval(zero, big.NewRat(1, 1), zero), "t"); err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+	for _, expression := range []string{"x^1000000000", "(x^2 + 1)^2049", "2^100000000", "(x / 3)^20000000"} {
+		if _, err := runnablePolynomial.MapValues(expression, *polynomial.NewInterval(zero, big.NewRat(1, 1), zero), "t"); err != polynomial.ErrOverflow {
+			t.Error("Expected an overflow on expression", expression, "Got:", err)
+		}
+	}
+	testMapValuesHelper("(-1)^1000000001 + (x - x + 1)^1000000000 + x^4096 - x^4096", 0, "0", t)
+}
+
+func TestEval(t *testing.T) {
+	p := polynomial.Poly{big.NewRat(1, 1), big.NewRat(0, 1), big.NewRat(3, 1)}
+	if value := p.Eval(big.NewRat(1, 2)); value.Cmp(big.NewRat(7, 4)) != 0 {
+		t.Error("Eval failed. Expected: 7/4 Got:", value)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	testMapValuesHelper("sum(k, 0, t, x^k)", 3, "x^3 + x^2 + x + 1", t)
+	testMapValuesHelper("prod(k, 1, t, x - k)", 2, "x^2 - 3*x + 2", t)
+}
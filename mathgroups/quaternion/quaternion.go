@@ -0,0 +1,350 @@
+// Package quaternion is an implementation of the quaternions with common operators and functions.
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Number is a struct representing the quaternion W + Xi + Yj + Zk
+type Number struct {
+	W float64
+	X float64
+	Y float64
+	Z float64
+}
+
+// Common functions and operations defined for the quaternion group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Conj
+	Norm
+	Normalize
+	Exp
+	Log
+	Sum
+	Prod
+)
+
+func (q Number) scale(s float64) Number {
+	return Number{q.W * s, q.X * s, q.Y * s, q.Z * s}
+}
+
+func (q Number) conj() Number {
+	return Number{q.W, -q.X, -q.Y, -q.Z}
+}
+
+func (q Number) dot(r Number) float64 {
+	return q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+}
+
+// Abs returns the norm of the quaternion.
+func (q Number) Abs() float64 {
+	return math.Sqrt(q.dot(q))
+}
+
+// Returns the norm of the vector part
+func (q Number) vectorAbs() float64 {
+	return math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+}
+
+func (q Number) inverse() (Number, error) {
+	abs2 := q.dot(q)
+	if abs2 == 0 {
+		return Number{}, types.ErrDivisionByZero
+	}
+	return q.conj().scale(1 / abs2), nil
+}
+
+func opAdd(params ...Number) Number {
+	p, q := params[0], params[1]
+	return Number{p.W + q.W, p.X + q.X, p.Y + q.Y, p.Z + q.Z}
+}
+
+func opSubtract(params ...Number) Number {
+	p, q := params[0], params[1]
+	return Number{p.W - q.W, p.X - q.X, p.Y - q.Y, p.Z - q.Z}
+}
+
+// The Hamilton product, which is not commutative
+func opMultiply(params ...Number) Number {
+	p, q := params[0], params[1]
+	return Number{
+		W: p.W*q.W - p.X*q.X - p.Y*q.Y - p.Z*q.Z,
+		X: p.W*q.X + p.X*q.W + p.Y*q.Z - p.Z*q.Y,
+		Y: p.W*q.Y - p.X*q.Z + p.Y*q.W + p.Z*q.X,
+		Z: p.W*q.Z + p.X*q.Y - p.Y*q.X + p.Z*q.W,
+	}
+}
+
+// Right division, p * q^-1
+func opDivide(params ...Number) (Number, error) {
+	inverse, err := params[1].inverse()
+	if err != nil {
+		return Number{}, err
+	}
+	return opMultiply(params[0], inverse), nil
+}
+
+func fnNormalize(params ...Number) (Number, error) {
+	abs := params[0].Abs()
+	if abs == 0 {
+		return Number{}, errors.New("cannot normalize the zero quaternion")
+	}
+	return params[0].scale(1 / abs), nil
+}
+
+func fnExp(params ...Number) Number {
+	q := params[0]
+	exp := math.Exp(q.W)
+	theta := q.vectorAbs()
+	if theta == 0 {
+		return Number{W: exp}
+	}
+	v := Number{0, q.X, q.Y, q.Z}.scale(exp * math.Sin(theta) / theta)
+	v.W = exp * math.Cos(theta)
+	return v
+}
+
+// The principal logarithm. The logarithm of a negative real number is taken about the i axis.
+func fnLog(params ...Number) (Number, error) {
+	q := params[0]
+	abs := q.Abs()
+	if abs == 0 {
+		return Number{}, errors.New("logarithm of 0 is undefined")
+	}
+	vectorAbs := q.vectorAbs()
+	if vectorAbs == 0 {
+		if q.W < 0 {
+			return Number{W: math.Log(abs), X: math.Pi}, nil
+		}
+		return Number{W: math.Log(abs)}, nil
+	}
+	v := Number{0, q.X, q.Y, q.Z}.scale(math.Atan2(vectorAbs, q.W) / vectorAbs)
+	v.W = math.Log(abs)
+	return v, nil
+}
+
+// Raises q to the power p. Real integer powers are computed by repeated multiplication,
+// and any other power as exp(log(q) * p).
+func pow(q Number, p Number) (Number, error) {
+	if p.X == 0 && p.Y == 0 && p.Z == 0 && p.W == math.Trunc(p.W) && math.Abs(p.W) <= math.MaxInt32 {
+		n := int64(p.W)
+		if n < 0 {
+			inverse, err := q.inverse()
+			if err != nil {
+				return Number{}, err
+			}
+			q, n = inverse, -n
+		}
+		result := Number{W: 1}
+		for ; n > 0; n >>= 1 {
+			if n&1 == 1 {
+				result = opMultiply(result, q)
+			}
+			q = opMultiply(q, q)
+		}
+		return result, nil
+	}
+	if q == (Number{}) {
+		if p.X == 0 && p.Y == 0 && p.Z == 0 && p.W > 0 {
+			return Number{}, nil
+		}
+		return Number{}, types.ErrDivisionByZero
+	}
+	log, err := fnLog(q)
+	if err != nil {
+		return Number{}, err
+	}
+	return fnExp(opMultiply(log, p)), nil
+}
+
+// Converts a real integral value to an int, for the bounds of a series
+func toInt(q Number) (int, bool) {
+	isReal := q.X == 0 && q.Y == 0 && q.Z == 0
+	return int(q.W), isReal && q.W == math.Trunc(q.W) && math.Abs(q.W) <= math.MaxInt32
+}
+
+var quaternionTokenMap = map[types.Keyword]types.KeywordData[Number]{
+	Add:      {Symbol: "+", TokenType: types.Operator, Apply: types.Infallible(opAdd)},
+	Subtract: {Symbol: "-", TokenType: types.Operator, Apply: types.Infallible(opSubtract)},
+	Multiply: {Symbol: "*", TokenType: types.Operator, Apply: types.Infallible(opMultiply)},
+	Divide:   {Symbol: "/", TokenType: types.Operator, Apply: opDivide},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...Number) (Number, error) {
+			return pow(params[0], params[1])
+		},
+	},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Number) (Number, error) {
+			return params[0].scale(-1), nil
+		},
+	},
+	Conj: {Symbol: "conj", TokenType: types.SingleFunction,
+		Apply: func(params ...Number) (Number, error) {
+			return params[0].conj(), nil
+		},
+	},
+	Norm: {Symbol: "norm", TokenType: types.SingleFunction,
+		Apply: func(params ...Number) (Number, error) {
+			return Number{W: params[0].Abs()}, nil
+		},
+	},
+	Normalize: {Symbol: "normalize", TokenType: types.SingleFunction, Apply: fnNormalize},
+	Exp:       {Symbol: "exp", TokenType: types.SingleFunction, Apply: types.Infallible(fnExp)},
+	Log:       {Symbol: "log", TokenType: types.SingleFunction, Apply: fnLog},
+	Sum: types.Series("sum", Number{}, func(p Number, q Number) (Number, error) {
+		return opAdd(p, q), nil
+	}, toInt),
+	// prod multiplies the values of its body in order, with each on the right
+	Prod: types.Series("prod", Number{W: 1}, func(p Number, q Number) (Number, error) {
+		return opMultiply(p, q), nil
+	}, toInt),
+}
+
+var quaternionStringToToken = map[string]types.Keyword{
+	"+":         Add,
+	"-":         Subtract,
+	"*":         Multiply,
+	"/":         Divide,
+	"^":         Power,
+	"neg":       Negate,
+	"conj":      Conj,
+	"norm":      Norm,
+	"normalize": Normalize,
+	"exp":       Exp,
+	"log":       Log,
+	"sum":       Sum,
+	"prod":      Prod,
+}
+
+var quaternionOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses real numbers, literals like "1_2_3_4" for 1 + 2i + 3j + 4k,
+// and multiples of a unit like "2.5j" or "1k". A unit always has a coefficient, so that a bare "k"
+// is a variable, as in sum(k, 1, n, k * 1i).
+func getQuaternion(s string) (Number, bool) {
+	if strings.Contains(s, "_") {
+		parts := strings.Split(s, "_")
+		if len(parts) != 4 {
+			return Number{}, false
+		}
+		var components [4]float64
+		for i, part := range parts {
+			x, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return Number{}, false
+			}
+			components[i] = x
+		}
+		return Number{components[0], components[1], components[2], components[3]}, true
+	}
+	if x, err := strconv.ParseFloat(s, 64); err == nil {
+		return Number{W: x}, true
+	}
+	if len(s) < 2 {
+		return Number{}, false
+	}
+	x, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return Number{}, false
+	}
+	switch s[len(s)-1] {
+	case 'i':
+		return Number{X: x}, true
+	case 'j':
+		return Number{Y: x}, true
+	case 'k':
+		return Number{Z: x}, true
+	}
+	return Number{}, false
+}
+
+// Formats a quaternion like "1 + 2i - 3j + 4k"
+func formatQuaternion(q Number) string {
+	s := ""
+	for _, part := range []struct {
+		x    float64
+		unit string
+	}{{q.W, ""}, {q.X, "i"}, {q.Y, "j"}, {q.Z, "k"}} {
+		if part.x == 0 {
+			continue
+		}
+		abs := strconv.FormatFloat(math.Abs(part.x), 'g', -1, 64)
+		if abs == "1" && part.unit != "" {
+			abs = ""
+		}
+		switch {
+		case s == "" && part.x < 0:
+			s = "-"
+		case s != "" && part.x < 0:
+			s += " - "
+		case s != "":
+			s += " + "
+		}
+		s += abs + part.unit
+	}
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// Quaternion represents the quaternions (float64, float64, float64, float64) and some defined operations/functions.
+// Multiplication is the Hamilton product, and p / q is p * q^-1.
+var Quaternion = types.NewMathGroup(quaternionTokenMap, quaternionStringToToken, quaternionOperatorPrecedence, getQuaternion, types.WithName[Number]("quaternion"), types.WithFormat(formatQuaternion))
+
+// Returns the distance between p and q, as the size of the logarithm of p^-1 * q
+func distance(p Number, q Number) float64 {
+	inverse, _ := p.inverse()
+	log, _ := fnLog(opMultiply(inverse, q))
+	return log.Abs()
+}
+
+// NewSlerpInterval constructs an interval that moves from start to end in the given number of equal steps.
+// For unit quaternions, this is spherical linear interpolation (slerp) between two rotations,
+// taking the shortest path: end may be replaced by -end, which represents the same rotation.
+func NewSlerpInterval(start Number, end Number, steps int) *types.Interval[Number] {
+	if steps < 1 || start == (Number{}) || end == (Number{}) {
+		panic("Invalid interval")
+	}
+	if start.dot(end) < 0 {
+		end = end.scale(-1)
+	}
+	inverse, _ := start.inverse()
+	log, _ := fnLog(opMultiply(inverse, end))
+	step := fnExp(log.scale(1 / float64(steps)))
+	// Within half a step of the end, accounting for rounding when there is no distance to cover
+	threshold := log.Abs()/float64(steps)/2 + 1e-9
+	return &types.Interval[Number]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur Number) (Number, bool) {
+			if distance(cur, end) <= threshold {
+				return end, true
+			}
+			next := opMultiply(cur, step)
+			if distance(next, end) <= threshold {
+				return end, false
+			}
+			return next, false
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package quaternion_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/quaternion"
+	"github.com/yasteen/go-parse/run"
+)
+
+var MIN_THRESHOLD = math.Pow10(-10)
+
+func equalEnough(a quaternion.Number, b quaternion.Number) bool {
+	return math.Abs(a.W-b.W) < MIN_THRESHOLD && math.Abs(a.X-b.X) < MIN_THRESHOLD &&
+		math.Abs(a.Y-b.Y) < MIN_THRESHOLD && math.Abs(a.Z-b.Z) < MIN_THRESHOLD
+}
+
+func testMapValuesHelper(expression string, input quaternion.Number, expected quaternion.Number, t *testing.T) {
+	runnableQuaternion := run.GetRunnableMathGroup(quaternion.Quaternion)
+	q, err := runnableQuaternion.MapValues(expression, *quaternion.NewSlerpInterval(input, input, 1), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !equalEnough(q[0], expected) {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", q[0])
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("1i * 1j", quaternion.Number{W: 1}, quaternion.Number{Z: 1}, t)
+	testMapValuesHelper("1j * 1i", quaternion.Number{W: 1}, quaternion.Number{Z: -1}, t)
+	testMapValuesHelper("1i * 1j * 1k", quaternion.Number{W: 1}, quaternion.Number{W: -1}, t)
+	testMapValuesHelper("x + 1_2_3_4", quaternion.Number{1, 1, 1, 1}, quaternion.Number{2, 3, 4, 5}, t)
+	testMapValuesHelper("x * 2j - 3", quaternion.Number{1, 1, 0, 0}, quaternion.Number{-3, 0, 2, 2}, t)
+	testMapValuesHelper("x / x", quaternion.Number{1, 2, 3, 4}, quaternion.Number{W: 1}, t)
+	testMapValuesHelper("x * conj(x)", quaternion.Number{1, 2, 3, 4}, quaternion.Number{W: 30}, t)
+	testMapValuesHelper("norm(x)", quaternion.Number{1, 2, 2, 4}, quaternion.Number{W: 5}, t)
+	testMapValuesHelper("normalize(x)", quaternion.Number{0, 3, 0, 4}, quaternion.Number{0, 0.6, 0, 0.8}, t)
+	testMapValuesHelper("-x^2", quaternion.Number{0, 1, 1, 0}, quaternion.Number{W: 2}, t)
+	testMapValuesHelper("x^-1 * x", quaternion.Number{1, 2, 3, 4}, quaternion.Number{W: 1}, t)
+}
+
+func TestExpLog(t *testing.T) {
+	testMapValuesHelper("exp(x * 3.14159265358979323846 / 2)", quaternion.Number{0, 0, 0, 1}, quaternion.Number{Z: 1}, t)
+	testMapValuesHelper("exp(log(x))", quaternion.Number{1, -2, 3, 0.5}, quaternion.Number{1, -2, 3, 0.5}, t)
+	testMapValuesHelper("log(-1)", quaternion.Number{W: 1}, quaternion.Number{X: math.Pi}, t)
+	testMapValuesHelper("x^0.5 * x^0.5", quaternion.Number{1, 2, 3, 4}, quaternion.Number{1, 2, 3, 4}, t)
+}
+
+func TestErrors(t *testing.T) {
+	runnableQuaternion := run.GetRunnableMathGroup(quaternion.Quaternion)
+	for _, expression := range []string{"x / 0", "log(0)", "normalize(x - x)", "0^-1", "i * j"} {
+		if _, err := runnableQuaternion.MapValues(expression, *quaternion.NewSlerpInterval(quaternion.Number{W: 1}, quaternion.Number{W: 1}, 1), "x"); err == nil {
+			t.Error("Expected an error on expression", expression)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	for q, expected := range map[quaternion.Number]string{
+		{1, 2, -3, 4}:   "1 + 2i - 3j + 4k",
+		{0, -1, 0, 1}:   "-i + k",
+		{-2.5, 0, 0, 0}: "-2.5",
+		{}:              "0",
+	} {
+		if output := quaternion.Quaternion.FormatValue(q); output != expected {
+			t.Errorf("FormatValue failed. Expected '%s', got '%s'", expected, output)
+		}
+	}
+}
+
+func TestSlerpInterval(t *testing.T) {
+	// A quarter turn about the z axis, in two steps of an eighth turn
+	end := quaternion.Number{W: math.Cos(math.Pi / 4), Z: math.Sin(math.Pi / 4)}
+	values := quaternion.NewSlerpInterval(quaternion.Number{W: 1}, end, 2).Values()
+	if len(values) != 3 {
+		t.Fatal("Slerp interval produced the wrong number of values:", values)
+	}
+	if middle := (quaternion.Number{W: math.Cos(math.Pi / 8), Z: math.Sin(math.Pi / 8)}); !equalEnough(values[1], middle) {
+		t.Error("Slerp interval produced the wrong midpoint. Expected:", middle, "Got:", values[1])
+	}
+	if values[2] != end {
+		t.Error("Slerp interval does not end at its end. Expected:", end, "Got:", values[2])
+	}
+
+	// The shortest path to -end is the same as the path to end
+	values = quaternion.NewSlerpInterval(quaternion.Number{W: 1}, quaternion.Number{W: -end.W, Z: -end.Z}, 2).Values()
+	if len(values) != 3 || !equalEnough(values[2], end) {
+		t.Error("Slerp interval did not take the shortest path:", values)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	testMapValuesHelper("sum(k, 1, 3, k)", quaternion.Number{W: 1}, quaternion.Number{W: 6}, t)
+	testMapValuesHelper("sum(k, 1, 3, k * 1k)", quaternion.Number{W: 1}, quaternion.Number{Z: 6}, t)
+	testMapValuesHelper("prod(k, 1, 2, x + k * 1i)", quaternion.Number{0, 0, 1, 0}, quaternion.Number{-3, 0, 0, -1}, t)
+}
@@ -0,0 +1,156 @@
+// Package rational is an implementation of the exact rational number system (*big.Rat) with common operators.
+package rational
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Common functions and operations defined for the rational group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Sum
+	Prod
+)
+
+// ErrOverflow is returned when a power would be too large to compute.
+var ErrOverflow = errors.New("rational overflow")
+
+// The largest number of bits in the numerator or denominator of a power
+const maxPowerBits = 1 << 24
+
+// Raises a rational number to an integer power. Powers whose numerator or denominator would have more than
+// maxPowerBits bits are reported with ErrOverflow, rather than being computed.
+func pow(base *big.Rat, exponent *big.Rat) (*big.Rat, error) {
+	if !exponent.IsInt() {
+		return nil, errors.New("exponent must be an integer")
+	}
+	n := new(big.Int).Abs(exponent.Num())
+	bits := base.Num().BitLen()
+	if base.Denom().BitLen() > bits {
+		bits = base.Denom().BitLen()
+	}
+	// Powers of 0, 1 and -1 stay small
+	if bits > 1 && new(big.Int).Mul(n, big.NewInt(int64(bits))).Cmp(big.NewInt(maxPowerBits)) > 0 {
+		return nil, ErrOverflow
+	}
+	num := new(big.Int).Exp(base.Num(), n, nil)
+	denom := new(big.Int).Exp(base.Denom(), n, nil)
+	if exponent.Sign() >= 0 {
+		return new(big.Rat).SetFrac(num, denom), nil
+	}
+	if num.Sign() == 0 {
+		return nil, types.ErrDivisionByZero
+	}
+	return new(big.Rat).SetFrac(denom, num), nil
+}
+
+// Converts an integral value to an int, for the bounds of a series
+func toInt(x *big.Rat) (int, bool) {
+	if !x.IsInt() || x.Num().CmpAbs(big.NewInt(math.MaxInt32)) > 0 {
+		return 0, false
+	}
+	return int(x.Num().Int64()), true
+}
+
+var rationalTokenMap = map[types.Keyword]types.KeywordData[*big.Rat]{
+	Add: {Symbol: "+", TokenType: types.Operator,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Add(params[0], params[1]), nil
+		}},
+	Subtract: {Symbol: "-", TokenType: types.Operator,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Sub(params[0], params[1]), nil
+		}},
+	Multiply: {Symbol: "*", TokenType: types.Operator,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Mul(params[0], params[1]), nil
+		}},
+	Divide: {Symbol: "/", TokenType: types.Operator,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			if params[1].Sign() == 0 {
+				return nil, types.ErrDivisionByZero
+			}
+			return new(big.Rat).Quo(params[0], params[1]), nil
+		}},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			return pow(params[0], params[1])
+		}},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...*big.Rat) (*big.Rat, error) {
+			return new(big.Rat).Neg(params[0]), nil
+		}},
+	Sum: types.Series("sum", new(big.Rat), func(x *big.Rat, y *big.Rat) (*big.Rat, error) {
+		return new(big.Rat).Add(x, y), nil
+	}, toInt),
+	Prod: types.Series("prod", big.NewRat(1, 1), func(x *big.Rat, y *big.Rat) (*big.Rat, error) {
+		return new(big.Rat).Mul(x, y), nil
+	}, toInt),
+}
+
+var rationalStringToToken = map[string]types.Keyword{
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"neg":  Negate,
+	"sum":  Sum,
+	"prod": Prod,
+}
+
+var rationalOperatorPrecedence = map[types.Keyword]int{
+	Add:      1,
+	Subtract: 1,
+	Multiply: 2,
+	Divide:   2,
+	Negate:   2,
+	Power:    3,
+}
+
+// Parses integers, fractions like "3/4", and decimals like "0.125" exactly
+func getRational(s string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(s)
+}
+
+// Format formats a rational number as a fraction, such as "1/2", or as an integer if it is one.
+func Format(r *big.Rat) string {
+	return r.RatString()
+}
+
+// FormatDecimal formats a rational number as a decimal, rounded to the given number of digits after the decimal point.
+func FormatDecimal(r *big.Rat, digits int) string {
+	return r.FloatString(digits)
+}
+
+// Rational represents the rational number system (*big.Rat) and some defined operations.
+// Values are never modified in place.
+var Rational = types.NewMathGroup(rationalTokenMap, rationalStringToToken, rationalOperatorPrecedence, getRational, types.WithName[*big.Rat]("rational"), types.WithFormat(Format))
+
+// NewInterval constructs a new rational interval.
+func NewInterval(start *big.Rat, step *big.Rat, end *big.Rat) *types.Interval[*big.Rat] {
+	if step.Sign() <= 0 || start.Cmp(end) > 0 {
+		panic("Invalid interval")
+	}
+	return &types.Interval[*big.Rat]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur *big.Rat) (*big.Rat, bool) {
+			next := new(big.Rat).Add(cur, step)
+			if next.Cmp(end) > 0 {
+				return end, true
+			}
+			return next, false
+		},
+	}
+}
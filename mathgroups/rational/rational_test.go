@@ -0,0 +1,61 @@
+package rational_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/rational"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, input string, expected string, t *testing.T) {
+	x, _ := new(big.Rat).SetString(input)
+	runnableRational := run.GetRunnableMathGroup(rational.Rational)
+	r, err := runnableRational.MapValues(expression, *rational.NewInterval(x, big.NewRat(1, 1), x), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := rational.Format(r[0]); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	testMapValuesHelper("1/3 + 1/6", "0", "1/2", t)
+	testMapValuesHelper("x * 0.125", "8", "1", t)
+	testMapValuesHelper("x - 3/4", "1/4", "-1/2", t)
+	testMapValuesHelper("(2/3)^-2 + x^3", "-1/2", "17/8", t)
+	testMapValuesHelper("-x / 7", "0.7", "-1/10", t)
+}
+
+func TestFormatDecimal(t *testing.T) {
+	if output := rational.FormatDecimal(big.NewRat(1, 3), 4); output != "0.3333" {
+		t.Error("FormatDecimal failed. Expected: 0.3333 Got:", output)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	values := rational.NewInterval(big.NewRat(0, 1), big.NewRat(1, 3), big.NewRat(1, 1)).Values()
+	if len(values) != 4 || values[3].Cmp(big.NewRat(1, 1)) != 0 {
+		t.Error("Interval produced the wrong values:", values)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	testMapValuesHelper("sum(k, 1, x, 1 / (k * (k + 1)))", "9", "9/10", t)
+	testMapValuesHelper("prod(k, 2, x, 1 - 1/k^2)", "4", "5/8", t)
+	testMapValuesHelper("sum(k, 1, x, k)", "0", "0", t)
+}
+
+func TestPowerOverflow(t *testing.T) {
+	testMapValuesHelper("x^10000000000 - (-x)^-10000000001", "1", "2", t)
+	runnableRational := run.GetRunnableMathGroup(rational.Rational)
+	for _, expression := range []string{"2^10000000000", "(1/2)^-10000000000", "x^(2^40)"} {
+		if _, err := runnableRational.MapValues(expression, *rational.NewInterval(big.NewRat(3, 1), big.NewRat(1, 1), big.NewRat(3, 1)), "x"); err != rational.ErrOverflow {
+			t.Error("Expected an overflow on expression", expression, "Got:", err)
+		}
+	}
+}
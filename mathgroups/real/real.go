@@ -20,73 +20,307 @@ const (
 	Tan
 	Log
 	Exp
+	Factorial
+	Percent
+	Not
+	Negate
+	Less
+	Greater
+	LessEqual
+	GreaterEqual
+	Equal
+	NotEqual
+	And
+	Or
+	If
+	Sqrt
+	Cbrt
+	Abs
+	Sign
+	Floor
+	Ceil
+	Round
+	Trunc
+	Asin
+	Acos
+	Atan
+	Sinh
+	Cosh
+	Tanh
+	Asinh
+	Acosh
+	Atanh
+	Log10
+	Log2
+	Min
+	Max
+	Mod
+	Degree
+	Sum
+	Prod
 )
 
-var realTokenMap = map[types.Keyword]types.KeywordData[float64]{
-	Add: {Symbol: "+", TokenType: types.Operator,
-		Apply: func(params ...float64) float64 {
-			return params[0] + params[1]
-		}},
-	Subtract: {Symbol: "-", TokenType: types.Operator,
-		Apply: func(params ...float64) float64 {
-			return params[0] - params[1]
-		}},
-	Multiply: {Symbol: "*", TokenType: types.Operator,
-		Apply: func(params ...float64) float64 {
-			return params[0] * params[1]
-		}},
-	Divide: {Symbol: "/", TokenType: types.Operator,
-		Apply: func(params ...float64) float64 {
-			if params[1] == 0 {
-				panic("Attempted division by 0")
-			}
-			return params[0] / params[1]
-		}},
-	Power: {Symbol: "^", TokenType: types.Operator,
-		Apply: func(params ...float64) float64 {
-			return math.Pow(params[0], params[1])
-		}},
-	Sin: {Symbol: "sin", TokenType: types.SingleFunction,
-		Apply: func(params ...float64) float64 {
-			return math.Sin(params[0])
-		}},
-	Cos: {Symbol: "cos", TokenType: types.SingleFunction,
-		Apply: func(params ...float64) float64 {
-			return math.Cos(params[0])
-		}},
-	Tan: {Symbol: "tan", TokenType: types.SingleFunction,
-		Apply: func(params ...float64) float64 {
-			return math.Tan(params[0])
-		}},
-	Log: {Symbol: "log", TokenType: types.SingleFunction,
-		Apply: func(params ...float64) float64 {
-			return math.Log(params[0])
-		}},
-	Exp: {Symbol: "exp", TokenType: types.SingleFunction,
-		Apply: func(params ...float64) float64 {
-			return math.Exp(params[0])
-		}},
+// Converts a boolean into 1 (true) or 0 (false)
+func fromBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Adapts a function of one real number into an Apply function
+func unary(f func(float64) float64) func(...float64) (float64, error) {
+	return func(params ...float64) (float64, error) {
+		return f(params[0]), nil
+	}
+}
+
+// Returns -1, 0 or 1 depending on the sign of x, or NaN if x is NaN
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return x
+}
+
+// Returns the remainder of x / y with the sign of y, like x - y * floor(x / y)
+func mod(x float64, y float64) float64 {
+	r := math.Mod(x, y)
+	if r != 0 && (r < 0) != (y < 0) {
+		r += y
+	}
+	return r
+}
+
+// Converts an integral value to an int, for the bounds of a series
+func toInt(x float64) (int, bool) {
+	return int(x), x == math.Trunc(x) && math.Abs(x) <= math.MaxInt32
+}
+
+// Returns the keywords of a real group whose angles are in units of the given size, in radians
+func realTokenMap(unit float64) map[types.Keyword]types.KeywordData[float64] {
+	// Adapts a trigonometric function to read angles in the unit
+	fromAngle := func(f func(float64) float64) func(float64) float64 {
+		return func(x float64) float64 { return f(x * unit) }
+	}
+	// Adapts an inverse trigonometric function to return angles in the unit
+	toAngle := func(f func(float64) float64) func(float64) float64 {
+		return func(x float64) float64 { return f(x) / unit }
+	}
+	return map[types.Keyword]types.KeywordData[float64]{
+		Add: {Symbol: "+", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return params[0] + params[1], nil
+			}},
+		Subtract: {Symbol: "-", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return params[0] - params[1], nil
+			}},
+		Multiply: {Symbol: "*", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return params[0] * params[1], nil
+			}},
+		Divide: {Symbol: "/", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				if params[1] == 0 {
+					return 0, types.ErrDivisionByZero
+				}
+				return params[0] / params[1], nil
+			}},
+		Power: {Symbol: "^", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Pow(params[0], params[1]), nil
+			}},
+		Sin: {Symbol: "sin", TokenType: types.SingleFunction, Apply: unary(fromAngle(math.Sin))},
+		Cos: {Symbol: "cos", TokenType: types.SingleFunction, Apply: unary(fromAngle(math.Cos))},
+		Tan: {Symbol: "tan", TokenType: types.SingleFunction, Apply: unary(fromAngle(math.Tan))},
+		Log: {Symbol: "log", TokenType: types.SingleFunction,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Log(params[0]), nil
+			}},
+		Exp: {Symbol: "exp", TokenType: types.SingleFunction,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Exp(params[0]), nil
+			}},
+		Factorial: {Symbol: "!", TokenType: types.Operator, Fixity: types.Postfix,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Gamma(params[0] + 1), nil
+			}},
+		Percent: {Symbol: "%", TokenType: types.Operator, Fixity: types.Postfix,
+			Apply: func(params ...float64) (float64, error) {
+				return params[0] / 100, nil
+			}},
+		Not: {Symbol: "not", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] == 0), nil
+			}},
+		Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+			Apply: func(params ...float64) (float64, error) {
+				return -params[0], nil
+			}},
+		Less: {Symbol: "<", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] < params[1]), nil
+			}},
+		Greater: {Symbol: ">", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] > params[1]), nil
+			}},
+		LessEqual: {Symbol: "<=", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] <= params[1]), nil
+			}},
+		GreaterEqual: {Symbol: ">=", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] >= params[1]), nil
+			}},
+		Equal: {Symbol: "==", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] == params[1]), nil
+			}},
+		NotEqual: {Symbol: "!=", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] != params[1]), nil
+			}},
+		And: {Symbol: "&&", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] != 0 && params[1] != 0), nil
+			}},
+		Or: {Symbol: "||", TokenType: types.Operator,
+			Apply: func(params ...float64) (float64, error) {
+				return fromBool(params[0] != 0 || params[1] != 0), nil
+			}},
+		If: {Symbol: "if", TokenType: types.SingleFunction, Arity: 3,
+			Apply: func(params ...float64) (float64, error) {
+				if params[0] != 0 {
+					return params[1], nil
+				}
+				return params[2], nil
+			},
+			Condition: func(param float64) bool {
+				return param != 0
+			}},
+		Sqrt:  {Symbol: "sqrt", TokenType: types.SingleFunction, Apply: unary(math.Sqrt)},
+		Cbrt:  {Symbol: "cbrt", TokenType: types.SingleFunction, Apply: unary(math.Cbrt)},
+		Abs:   {Symbol: "abs", TokenType: types.SingleFunction, Apply: unary(math.Abs)},
+		Sign:  {Symbol: "sign", TokenType: types.SingleFunction, Apply: unary(sign)},
+		Floor: {Symbol: "floor", TokenType: types.SingleFunction, Apply: unary(math.Floor)},
+		Ceil:  {Symbol: "ceil", TokenType: types.SingleFunction, Apply: unary(math.Ceil)},
+		Round: {Symbol: "round", TokenType: types.SingleFunction, Apply: unary(math.Round)},
+		Trunc: {Symbol: "trunc", TokenType: types.SingleFunction, Apply: unary(math.Trunc)},
+		Asin:  {Symbol: "asin", TokenType: types.SingleFunction, Apply: unary(toAngle(math.Asin))},
+		Acos:  {Symbol: "acos", TokenType: types.SingleFunction, Apply: unary(toAngle(math.Acos))},
+		Atan:  {Symbol: "atan", TokenType: types.SingleFunction, Apply: unary(toAngle(math.Atan))},
+		Sinh:  {Symbol: "sinh", TokenType: types.SingleFunction, Apply: unary(math.Sinh)},
+		Cosh:  {Symbol: "cosh", TokenType: types.SingleFunction, Apply: unary(math.Cosh)},
+		Tanh:  {Symbol: "tanh", TokenType: types.SingleFunction, Apply: unary(math.Tanh)},
+		Asinh: {Symbol: "asinh", TokenType: types.SingleFunction, Apply: unary(math.Asinh)},
+		Acosh: {Symbol: "acosh", TokenType: types.SingleFunction, Apply: unary(math.Acosh)},
+		Atanh: {Symbol: "atanh", TokenType: types.SingleFunction, Apply: unary(math.Atanh)},
+		Log10: {Symbol: "log10", TokenType: types.SingleFunction, Apply: unary(math.Log10)},
+		Log2:  {Symbol: "log2", TokenType: types.SingleFunction, Apply: unary(math.Log2)},
+		Min: {Symbol: "min", TokenType: types.SingleFunction, Arity: 2,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Min(params[0], params[1]), nil
+			}},
+		Max: {Symbol: "max", TokenType: types.SingleFunction, Arity: 2,
+			Apply: func(params ...float64) (float64, error) {
+				return math.Max(params[0], params[1]), nil
+			}},
+		Mod: {Symbol: "mod", TokenType: types.SingleFunction, Arity: 2,
+			Apply: func(params ...float64) (float64, error) {
+				if params[1] == 0 {
+					return 0, types.ErrDivisionByZero
+				}
+				return mod(params[0], params[1]), nil
+			}},
+		// x° is an angle of x degrees, in the unit of the group
+		Degree: {Symbol: "°", TokenType: types.Operator, Fixity: types.Postfix,
+			Apply: func(params ...float64) (float64, error) {
+				return params[0] * types.Degrees.Size() / unit, nil
+			}},
+		Sum: types.Series("sum", 0, func(x float64, y float64) (float64, error) {
+			return x + y, nil
+		}, toInt),
+		Prod: types.Series("prod", 1, func(x float64, y float64) (float64, error) {
+			return x * y, nil
+		}, toInt),
+	}
 }
 
 var realStringToToken = map[string]types.Keyword{
-	"+":   Add,
-	"-":   Subtract,
-	"*":   Multiply,
-	"/":   Divide,
-	"^":   Power,
-	"sin": Sin,
-	"cos": Cos,
-	"tan": Tan,
-	"log": Log,
-	"exp": Exp,
+	"+":     Add,
+	"-":     Subtract,
+	"*":     Multiply,
+	"/":     Divide,
+	"^":     Power,
+	"sin":   Sin,
+	"cos":   Cos,
+	"tan":   Tan,
+	"log":   Log,
+	"exp":   Exp,
+	"!":     Factorial,
+	"%":     Percent,
+	"not":   Not,
+	"neg":   Negate,
+	"<":     Less,
+	">":     Greater,
+	"<=":    LessEqual,
+	">=":    GreaterEqual,
+	"==":    Equal,
+	"!=":    NotEqual,
+	"&&":    And,
+	"||":    Or,
+	"if":    If,
+	"sqrt":  Sqrt,
+	"cbrt":  Cbrt,
+	"abs":   Abs,
+	"sign":  Sign,
+	"floor": Floor,
+	"ceil":  Ceil,
+	"round": Round,
+	"trunc": Trunc,
+	"asin":  Asin,
+	"acos":  Acos,
+	"atan":  Atan,
+	"sinh":  Sinh,
+	"cosh":  Cosh,
+	"tanh":  Tanh,
+	"asinh": Asinh,
+	"acosh": Acosh,
+	"atanh": Atanh,
+	"log10": Log10,
+	"log2":  Log2,
+	"min":   Min,
+	"max":   Max,
+	"mod":   Mod,
+	"°":     Degree,
+	"sum":   Sum,
+	"prod":  Prod,
 }
 
 var realOperatorPrecedence = map[types.Keyword]int{
-	Add:      1,
-	Subtract: 1,
-	Multiply: 2,
-	Divide:   2,
-	Power:    3,
+	Or:           1,
+	And:          2,
+	Not:          3,
+	Less:         4,
+	Greater:      4,
+	LessEqual:    4,
+	GreaterEqual: 4,
+	Equal:        4,
+	NotEqual:     4,
+	Add:          5,
+	Subtract:     5,
+	Multiply:     6,
+	Divide:       6,
+	Negate:       6,
+	Power:        7,
+	Factorial:    8,
+	Percent:      8,
+	Degree:       8,
 }
 
 func getReal(s string) (float64, bool) {
@@ -96,8 +330,22 @@ func getReal(s string) (float64, bool) {
 	return 0, false
 }
 
-// Real represents real number system (float64) and some defined operations/functions
-var Real = types.NewMathGroup(realTokenMap, realStringToToken, realOperatorPrecedence, getReal)
+func formatReal(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Real represents real number system (float64) and some defined operations/functions.
+// Functions outside of their domain give NaN, like the math package: sqrt, log, log10 and log2 of
+// negative numbers, asin and acos outside of [-1, 1], acosh below 1, and atanh outside of [-1, 1].
+// Division and mod by zero are errors. round rounds halves away from zero, and mod(x, y) has the sign of y.
+var Real = New(types.Radians)
+
+// New constructs a real group whose trigonometric functions read and return angles in the given mode.
+// In any mode, x° is an angle of x degrees. The group is named after its mode, such as "real(degrees)".
+func New(mode types.AngleMode) *types.MathGroup[float64] {
+	return types.NewMathGroup(realTokenMap(mode.Size()), realStringToToken, realOperatorPrecedence, getReal,
+		types.WithName[float64]("real("+mode.String()+")"), types.WithFormat(formatReal), types.WithAngleMode[float64](mode))
+}
 
 // NewInterval constructs a new real interval.
 func NewInterval(start float64, step float64, end float64) *types.Interval[float64] {
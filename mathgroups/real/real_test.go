@@ -0,0 +1,74 @@
+package real_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/run"
+	"github.com/yasteen/go-parse/types"
+)
+
+var MIN_THRESHOLD = math.Pow10(-10)
+
+func testMapValuesHelper(expression string, input float64, expected float64, t *testing.T) {
+	testGroupHelper(real.Real, expression, input, expected, t)
+}
+
+func testGroupHelper(group *types.MathGroup[float64], expression string, input float64, expected float64, t *testing.T) {
+	runnableReal := run.GetRunnableMathGroup(group)
+	r, err := runnableReal.MapValues(expression, *real.NewInterval(input, 1, input), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if math.IsNaN(expected) != math.IsNaN(r[0]) || math.Abs(r[0]-expected) > MIN_THRESHOLD {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", r[0])
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	testMapValuesHelper("sqrt(x) + cbrt(-27)", 16, 1, t)
+	testMapValuesHelper("abs(x) * sign(x)", -2.5, -2.5, t)
+	testMapValuesHelper("sign(x)", 0, 0, t)
+	testMapValuesHelper("floor(x) + ceil(x)", -1.5, -3, t)
+	testMapValuesHelper("round(x) + round(-x) + trunc(x)", 2.5, 2, t)
+	testMapValuesHelper("asin(x) + acos(x)", 0.3, math.Pi/2, t)
+	testMapValuesHelper("atan(x)", 1, math.Pi/4, t)
+	testMapValuesHelper("cosh(x)^2 - sinh(x)^2", 1.7, 1, t)
+	testMapValuesHelper("tanh(atanh(x)) + asinh(sinh(x)) + acosh(cosh(x))", 0.5, 1.5, t)
+	testMapValuesHelper("log10(x) + log2(8)", 1000, 6, t)
+	testMapValuesHelper("min(x, 2) + max(x, 2)", 5, 7, t)
+	testMapValuesHelper("mod(x, 3) + mod(-x, 3) + mod(x, -3)", 7, 1+2-2, t)
+}
+
+func TestDomains(t *testing.T) {
+	for _, expression := range []string{"sqrt(x)", "log10(x)", "log2(x)", "asin(x - 1)", "acos(x - 1)", "acosh(x + 1.5)", "atanh(x - 1)"} {
+		testMapValuesHelper(expression, -1, math.NaN(), t)
+	}
+	runnableReal := run.GetRunnableMathGroup(real.Real)
+	if _, err := runnableReal.MapValues("mod(x, 0)", *real.NewInterval(1, 1, 1), "x"); err == nil {
+		t.Error("Expected an error on expression mod(x, 0)")
+	}
+}
+
+func TestAngleModes(t *testing.T) {
+	degrees := real.New(types.Degrees)
+	gradians := real.New(types.Gradians)
+	if real.Real.AngleMode() != types.Radians || degrees.AngleMode() != types.Degrees || gradians.AngleMode().String() != "gradians" {
+		t.Error("Unexpected angle modes", real.Real.AngleMode(), degrees.AngleMode(), gradians.AngleMode())
+	}
+	if real.Real.Name != "real(radians)" || degrees.Name != "real(degrees)" {
+		t.Error("Unexpected names", real.Real.Name, degrees.Name)
+	}
+
+	testGroupHelper(degrees, "sin(x)", 30, 0.5, t)
+	testGroupHelper(degrees, "cos(x) + tan(45)", 180, 0, t)
+	testGroupHelper(degrees, "asin(x) + acos(0) + atan(1)", 1, 225, t)
+	testGroupHelper(degrees, "sin(x°)", 90, 1, t)
+	testGroupHelper(gradians, "cos(x) + asin(1)", 200, 99, t)
+	testGroupHelper(gradians, "x°", 90, 100, t)
+	testMapValuesHelper("sin(x°) + 90°", 30, 0.5+math.Pi/2, t)
+}
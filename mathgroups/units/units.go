@@ -0,0 +1,378 @@
+// Package units is an implementation of physical quantities, with magnitudes and SI dimensions,
+// and common operators and functions.
+package units
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Dimension is the exponent of each SI base unit, in the order m, kg, s, A, K, mol, cd.
+type Dimension [7]int
+
+// The symbols of the SI base units, in the order of a Dimension
+var baseUnits = [7]string{"m", "kg", "s", "A", "K", "mol", "cd"}
+
+// Dimensionless is the dimension of pure numbers.
+var Dimensionless = Dimension{}
+
+// Quantity is a magnitude in SI base units, along with its dimension.
+// Unit is the unit it is displayed in, which is SI base units if empty.
+type Quantity struct {
+	Value     float64
+	Dimension Dimension
+	Unit      string
+}
+
+// Common functions and operations defined for the units group
+const (
+	Add types.Keyword = iota
+	Subtract
+	Multiply
+	Divide
+	Power
+	Negate
+	Sqrt
+	Sin
+	Cos
+	Tan
+	Log
+	Exp
+	To
+	Juxtapose // A number followed by a unit, such as 3 km, which binds more tightly than * and /
+)
+
+func (d Dimension) times(e Dimension) Dimension {
+	for i := range d {
+		d[i] += e[i]
+	}
+	return d
+}
+
+func (d Dimension) scale(n int) Dimension {
+	for i := range d {
+		d[i] *= n
+	}
+	return d
+}
+
+// Formats a dimension like "kg*m^2/s^2", or "" if it is dimensionless
+func (d Dimension) String() string {
+	formatUnit := func(i int, exponent int) string {
+		if exponent == 1 {
+			return baseUnits[i]
+		}
+		return baseUnits[i] + "^" + strconv.Itoa(exponent)
+	}
+	numerator := []string{}
+	denominator := []string{}
+	for _, i := range []int{1, 0, 2, 3, 4, 5, 6} {
+		switch {
+		case d[i] > 0:
+			numerator = append(numerator, formatUnit(i, d[i]))
+		case d[i] < 0:
+			denominator = append(denominator, formatUnit(i, -d[i]))
+		}
+	}
+	if len(numerator) == 0 {
+		for i := range denominator {
+			denominator[i] = strings.Replace(denominator[i], "^", "^-", 1)
+			if !strings.Contains(denominator[i], "^") {
+				denominator[i] += "^-1"
+			}
+		}
+		return strings.Join(denominator, "*")
+	}
+	return strings.Join(append([]string{strings.Join(numerator, "*")}, denominator...), "/")
+}
+
+// A named unit, and its size in SI base units
+type unit struct {
+	factor    float64
+	dimension Dimension
+}
+
+var (
+	length      = Dimension{1, 0, 0, 0, 0, 0, 0}
+	mass        = Dimension{0, 1, 0, 0, 0, 0, 0}
+	time        = Dimension{0, 0, 1, 0, 0, 0, 0}
+	current     = Dimension{0, 0, 0, 1, 0, 0, 0}
+	temperature = Dimension{0, 0, 0, 0, 1, 0, 0}
+	amount      = Dimension{0, 0, 0, 0, 0, 1, 0}
+	luminosity  = Dimension{0, 0, 0, 0, 0, 0, 1}
+	force       = Dimension{1, 1, -2, 0, 0, 0, 0}
+	energy      = Dimension{2, 1, -2, 0, 0, 0, 0}
+	power       = Dimension{2, 1, -3, 0, 0, 0, 0}
+	charge      = Dimension{0, 0, 1, 1, 0, 0, 0}
+)
+
+var namedUnits = map[string]unit{
+	"m":   {1, length},
+	"km":  {1e3, length},
+	"cm":  {1e-2, length},
+	"mm":  {1e-3, length},
+	"in":  {0.0254, length},
+	"ft":  {0.3048, length},
+	"yd":  {0.9144, length},
+	"mi":  {1609.344, length},
+	"kg":  {1, mass},
+	"g":   {1e-3, mass},
+	"mg":  {1e-6, mass},
+	"lb":  {0.45359237, mass},
+	"s":   {1, time},
+	"ms":  {1e-3, time},
+	"min": {60, time},
+	"h":   {3600, time},
+	"A":   {1, current},
+	"K":   {1, temperature},
+	"mol": {1, amount},
+	"cd":  {1, luminosity},
+	"L":   {1e-3, length.scale(3)},
+	"Hz":  {1, time.scale(-1)},
+	"N":   {1, force},
+	"J":   {1, energy},
+	"kJ":  {1e3, energy},
+	"W":   {1, power},
+	"kW":  {1e3, power},
+	"Pa":  {1, force.times(length.scale(-2))},
+	"C":   {1, charge},
+	"V":   {1, power.times(current.scale(-1))},
+}
+
+// Parses a unit expression, such as "m/s^2" or "kg*m", into its size in SI base units.
+// Each unit may be raised to an integer power, and the units are multiplied or divided from left to right.
+func parseUnit(s string) (unit, bool) {
+	result := unit{factor: 1}
+	sign := 1
+	for len(s) > 0 {
+		end := strings.IndexAny(s, "*/")
+		if end < 0 {
+			end = len(s)
+		}
+		name, exponent := s[:end], 1
+		if i := strings.Index(name, "^"); i >= 0 {
+			var err error
+			if exponent, err = strconv.Atoi(name[i+1:]); err != nil {
+				return unit{}, false
+			}
+			name = name[:i]
+		}
+		u, ok := namedUnits[name]
+		if !ok {
+			return unit{}, false
+		}
+		exponent *= sign
+		result.factor *= math.Pow(u.factor, float64(exponent))
+		result.dimension = result.dimension.times(u.dimension.scale(exponent))
+		if end == len(s) {
+			break
+		}
+		if s[end] == '/' {
+			sign = -1
+		} else {
+			sign = 1
+		}
+		s = s[end+1:]
+		if len(s) == 0 {
+			return unit{}, false
+		}
+	}
+	return result, true
+}
+
+func incompatible(operation string, a Quantity, b Quantity) error {
+	return errors.New("cannot " + operation + " quantities of dimensions " + formatDimension(a.Dimension) + " and " + formatDimension(b.Dimension))
+}
+
+func formatDimension(d Dimension) string {
+	if d == Dimensionless {
+		return "1"
+	}
+	return d.String()
+}
+
+// Wraps a function of a dimensionless value
+func dimensionless(name string, f func(float64) float64) func(...Quantity) (Quantity, error) {
+	return func(params ...Quantity) (Quantity, error) {
+		if params[0].Dimension != Dimensionless {
+			return Quantity{}, errors.New(name + " requires a dimensionless argument, got " + formatDimension(params[0].Dimension))
+		}
+		return Quantity{Value: f(params[0].Value)}, nil
+	}
+}
+
+// Raises a quantity to a dimensionless power, which must give integer exponents to its dimension
+func pow(base Quantity, exponent Quantity) (Quantity, error) {
+	if exponent.Dimension != Dimensionless {
+		return Quantity{}, errors.New("exponent must be dimensionless, got " + formatDimension(exponent.Dimension))
+	}
+	var d Dimension
+	for i, e := range base.Dimension {
+		x := float64(e) * exponent.Value
+		if x != math.Trunc(x) || math.Abs(x) > math.MaxInt32 {
+			return Quantity{}, errors.New("cannot raise a quantity of dimension " + formatDimension(base.Dimension) + " to a fractional power")
+		}
+		d[i] = int(x)
+	}
+	return Quantity{Value: math.Pow(base.Value, exponent.Value), Dimension: d}, nil
+}
+
+func multiply(params ...Quantity) Quantity {
+	return Quantity{Value: params[0].Value * params[1].Value, Dimension: params[0].Dimension.times(params[1].Dimension)}
+}
+
+var unitsTokenMap = map[types.Keyword]types.KeywordData[Quantity]{
+	Add: {Symbol: "+", TokenType: types.Operator,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			if params[0].Dimension != params[1].Dimension {
+				return Quantity{}, incompatible("add", params[0], params[1])
+			}
+			return Quantity{Value: params[0].Value + params[1].Value, Dimension: params[0].Dimension}, nil
+		}},
+	Subtract: {Symbol: "-", TokenType: types.Operator,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			if params[0].Dimension != params[1].Dimension {
+				return Quantity{}, incompatible("subtract", params[0], params[1])
+			}
+			return Quantity{Value: params[0].Value - params[1].Value, Dimension: params[0].Dimension}, nil
+		}},
+	Multiply:  {Symbol: "*", TokenType: types.Operator, Apply: types.Infallible(multiply)},
+	Juxtapose: {Symbol: "·", TokenType: types.Operator, Implicit: true, Apply: types.Infallible(multiply)},
+	Divide: {Symbol: "/", TokenType: types.Operator,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			if params[1].Value == 0 {
+				return Quantity{}, types.ErrDivisionByZero
+			}
+			return Quantity{Value: params[0].Value / params[1].Value, Dimension: params[0].Dimension.times(params[1].Dimension.scale(-1))}, nil
+		}},
+	Power: {Symbol: "^", TokenType: types.Operator,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			return pow(params[0], params[1])
+		}},
+	Negate: {Symbol: "-", TokenType: types.Operator, Fixity: types.Prefix,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			return Quantity{Value: -params[0].Value, Dimension: params[0].Dimension, Unit: params[0].Unit}, nil
+		}},
+	Sqrt: {Symbol: "sqrt", TokenType: types.SingleFunction,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			if params[0].Value < 0 {
+				return Quantity{}, errors.New("square root of a negative quantity")
+			}
+			return pow(params[0], Quantity{Value: 0.5})
+		}},
+	Sin: {Symbol: "sin", TokenType: types.SingleFunction, Apply: dimensionless("sin", math.Sin)},
+	Cos: {Symbol: "cos", TokenType: types.SingleFunction, Apply: dimensionless("cos", math.Cos)},
+	Tan: {Symbol: "tan", TokenType: types.SingleFunction, Apply: dimensionless("tan", math.Tan)},
+	Log: {Symbol: "log", TokenType: types.SingleFunction, Apply: dimensionless("log", math.Log)},
+	Exp: {Symbol: "exp", TokenType: types.SingleFunction, Apply: dimensionless("exp", math.Exp)},
+	// to(x, "ft") displays x in the given unit, which must have the same dimension
+	To: {Symbol: "to", TokenType: types.SingleFunction, Arity: 2,
+		Apply: func(params ...Quantity) (Quantity, error) {
+			if params[1].Unit == "" {
+				return Quantity{}, errors.New("the unit to convert to must be quoted, such as \"ft\"")
+			}
+			if params[0].Dimension != params[1].Dimension {
+				return Quantity{}, incompatible("convert between", params[0], params[1])
+			}
+			return Quantity{Value: params[0].Value, Dimension: params[0].Dimension, Unit: params[1].Unit}, nil
+		}},
+}
+
+var unitsStringToToken = map[string]types.Keyword{
+	"+":    Add,
+	"-":    Subtract,
+	"*":    Multiply,
+	"/":    Divide,
+	"^":    Power,
+	"neg":  Negate,
+	"sqrt": Sqrt,
+	"sin":  Sin,
+	"cos":  Cos,
+	"tan":  Tan,
+	"log":  Log,
+	"exp":  Exp,
+	"to":   To,
+	"·":    Juxtapose,
+}
+
+var unitsOperatorPrecedence = map[types.Keyword]int{
+	Add:       1,
+	Subtract:  1,
+	Multiply:  2,
+	Divide:    2,
+	Negate:    2,
+	Juxtapose: 3,
+	Power:     4,
+}
+
+// Parses dimensionless numbers, units such as "km", and quoted unit expressions such as "\"km/h\"".
+// A quoted unit is displayed in that unit.
+func getQuantity(s string) (Quantity, bool) {
+	if x, err := strconv.ParseFloat(s, 64); err == nil {
+		return Quantity{Value: x}, true
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		u, ok := parseUnit(s[1 : len(s)-1])
+		return Quantity{Value: u.factor, Dimension: u.dimension, Unit: s[1 : len(s)-1]}, ok
+	}
+	if u, ok := namedUnits[s]; ok {
+		return Quantity{Value: u.factor, Dimension: u.dimension}, true
+	}
+	return Quantity{}, false
+}
+
+// Format formats a quantity in its unit, such as "9.81 m/s^2" or "32.2 ft/s^2".
+func Format(q Quantity) string {
+	value := q.Value
+	unitString := q.Dimension.String()
+	if q.Unit != "" {
+		u, _ := parseUnit(q.Unit)
+		value /= u.factor
+		unitString = q.Unit
+	}
+	s := strconv.FormatFloat(value, 'g', -1, 64)
+	if unitString == "" {
+		return s
+	}
+	return s + " " + unitString
+}
+
+// Units represents physical quantities and some defined operations/functions. Quantities can only be added
+// or compared when they have the same dimension, and functions like sin only accept dimensionless values.
+// A number followed by a unit, such as 3 km, is multiplied by it before any * or /, so 100 km / 2 h is 50 km/h.
+// Results are given in SI base units,
+// unless converted with to.
+var Units = types.NewMathGroup(unitsTokenMap, unitsStringToToken, unitsOperatorPrecedence, getQuantity, types.WithName[Quantity]("units"), types.WithFormat(Format))
+
+// NewInterval constructs a new interval of quantities, which must all have the same dimension.
+func NewInterval(start Quantity, step Quantity, end Quantity) *types.Interval[Quantity] {
+	if start.Dimension != step.Dimension || start.Dimension != end.Dimension || step.Value <= 0 || start.Value > end.Value {
+		panic("Invalid interval")
+	}
+	return &types.Interval[Quantity]{
+		Start: start,
+		Step:  step,
+		End:   end,
+		Next: func(cur Quantity) (Quantity, bool) {
+			next := Quantity{Value: cur.Value + step.Value, Dimension: cur.Dimension, Unit: cur.Unit}
+			if next.Value > end.Value {
+				return end, true
+			}
+			return next, false
+		},
+	}
+}
+
+// New constructs a quantity of the given magnitude in a unit, such as New(3, "km/h").
+// The quantity is displayed in that unit.
+func New(value float64, unitString string) (Quantity, error) {
+	u, ok := parseUnit(unitString)
+	if !ok {
+		return Quantity{}, errors.New("unknown unit " + unitString)
+	}
+	return Quantity{Value: value * u.factor, Dimension: u.dimension, Unit: unitString}, nil
+}
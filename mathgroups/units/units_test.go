@@ -0,0 +1,64 @@
+package units_test
+
+import (
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/units"
+	"github.com/yasteen/go-parse/run"
+)
+
+func testMapValuesHelper(expression string, input units.Quantity, expected string, t *testing.T) {
+	runnableUnits := run.GetRunnableMathGroup(units.Units)
+	r, err := runnableUnits.MapValues(expression, *units.NewInterval(input, units.Quantity{Value: 1, Dimension: input.Dimension}, input), "x")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if output := units.Format(r[0]); output != expected {
+		t.Error("Failed on expression", expression, "- Expected:", expected, "Got:", output)
+	}
+}
+
+func testMapValuesErrorHelper(expression string, t *testing.T) {
+	runnableUnits := run.GetRunnableMathGroup(units.Units)
+	if _, err := runnableUnits.MapValues(expression, *units.NewInterval(units.Quantity{}, units.Quantity{Value: 1}, units.Quantity{}), "x"); err == nil {
+		t.Error("Expected an error on expression", expression)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	seconds, _ := units.New(2, "s")
+	testMapValuesHelper("9.81 m/s^2 * x", seconds, "19.62 m/s", t)
+	testMapValuesHelper("3 km + 500 m", seconds, "3500 m", t)
+	testMapValuesHelper("1/2 * 9.81 m/s^2 * x^2", seconds, "19.62 m", t)
+	testMapValuesHelper("2 kg * 3 m / x^2", seconds, "1.5 kg*m/s^2", t)
+	testMapValuesHelper("sqrt(16 m^2)", seconds, "4 m", t)
+	testMapValuesHelper("1 / x", seconds, "0.5 s^-1", t)
+	testMapValuesHelper("sin(0) + exp(0) + (3 m) / (2 m)", seconds, "2.5", t)
+	testMapValuesHelper("-x", seconds, "-2 s", t)
+}
+
+func TestConversion(t *testing.T) {
+	hours, _ := units.New(2, "h")
+	testMapValuesHelper("to(x, \"min\")", hours, "120 min", t)
+	testMapValuesHelper("to(100 km / x, \"km/h\")", hours, "50 km/h", t)
+	testMapValuesHelper("to(3 ft * 4 ft, \"ft^2\")", hours, "12 ft^2", t)
+	testMapValuesHelper("x", hours, "2 h", t)
+	testMapValuesHelper("to(100 km / 2 h, \"km/h\")", hours, "50 km/h", t)
+	testMapValuesHelper("1 m / 2 s", hours, "0.5 m/s", t)
+	testMapValuesHelper("6 m^2 / 2 m", hours, "3 m", t)
+}
+
+func TestDimensionErrors(t *testing.T) {
+	testMapValuesErrorHelper("3 m + 2 s", t)
+	testMapValuesErrorHelper("3 m - 2", t)
+	testMapValuesErrorHelper("sin(3 m)", t)
+	testMapValuesErrorHelper("log(2 kg)", t)
+	testMapValuesErrorHelper("exp(1 s)", t)
+	testMapValuesErrorHelper("2^(1 m)", t)
+	testMapValuesErrorHelper("(2 m)^0.5", t)
+	testMapValuesErrorHelper("to(3 m, \"s\")", t)
+	testMapValuesErrorHelper("to(3 m, ft)", t)
+}
@@ -3,6 +3,7 @@ package parsexp
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/karalabe/cookiejar/collections/stack"
@@ -15,80 +16,234 @@ type ParsedExpression []string
 // GetNextTokenString returns a string with the next token.
 func GetNextTokenString[T any](expression string, index int, m *types.MathGroup[T]) (tokenString string, nextIndex int) {
 	tokenString = ""
-	for ; index < len(expression); index++ {
-		c := string(expression[index])
-		if c == " " {
+	for index < len(expression) {
+		if expression[index] == ' ' {
 			if tokenString == "" {
+				index++
 				continue
 			}
-			for index < len(expression) && string(expression[index]) == " " {
+			for index < len(expression) && expression[index] == ' ' {
 				index++
 			}
 			break
-		} else {
-			tokenType, _ := m.StringToTokenType(c)
-			if tokenType == types.RParen || tokenType == types.LParen || tokenType == types.Operator {
-				if tokenString == "" {
-					tokenString = c
-					index++
+		}
+		// A bracketed literal, such as a matrix, or a quoted string is a single token
+		if expression[index] == '[' || expression[index] == '"' {
+			if tokenString == "" {
+				end := matchingBracket(expression, index)
+				if expression[index] == '"' {
+					end = closingQuote(expression, index)
 				}
-				break
+				tokenString = expression[index:end]
+				index = end
 			}
+			break
+		}
+		if symbol := m.MatchSymbol(expression[index:]); symbol != "" {
+			if tokenString == "" {
+				tokenString = symbol
+				index += len(symbol)
+			}
+			break
 		}
-		tokenString += c
+		tokenString += expression[index : index+1]
+		index++
 	}
 	return tokenString, index
 }
 
+// Returns the index after the bracket closing the one at the given index,
+// or the end of the expression if it is never closed.
+func matchingBracket(expression string, index int) int {
+	depth := 0
+	for i := index; i < len(expression); i++ {
+		if expression[i] == '[' {
+			depth++
+		} else if expression[i] == ']' {
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(expression)
+}
+
+// Returns the index after the quote closing the one at the given index,
+// or the end of the expression if it is never closed.
+func closingQuote(expression string, index int) int {
+	if end := strings.IndexByte(expression[index+1:], '"'); end >= 0 {
+		return index + end + 2
+	}
+	return len(expression)
+}
+
 // IsLocallyValid verifies whether each token is valid with reference to its neighbours.
+// A comma is only valid within the parentheses of a function call.
 func IsLocallyValid[T any](tokens []string, m *types.MathGroup[T]) (bool, int) {
+	valid, currentCharLength, _ := checkLocally(tokens, m)
+	return valid, currentCharLength
+}
+
+// Implements IsLocallyValid, also returning the index of the first invalid token.
+// The index is -1 if the tokens are valid, or end where an operand is expected.
+func checkLocally[T any](tokens []string, m *types.MathGroup[T]) (bool, int, int) {
 	currentCharLength := 0
-	if len(tokens) == 0 {
-		return true, currentCharLength
-	}
-	prevTokenType, _ := m.StringToTokenType(tokens[0])
-	if len(tokens) == 1 {
-		return prevTokenType == types.Value || prevTokenType == types.Variable, currentCharLength
+	// Whether the next token must start an operand, rather than follow one
+	expectOperand := true
+	// Whether each open parenthesis surrounds the arguments of a function
+	calls := []bool{}
+	for i, t := range tokens {
+		tokenType, keyword := m.StringToTokenType(t)
+		fixity := m.Fixity(keyword)
+		valid := false
+		if expectOperand {
+			switch {
+			case tokenType == types.Value || tokenType == types.Variable:
+				valid, expectOperand = true, false
+			case tokenType == types.LParen:
+				prevType := types.Value
+				if i > 0 {
+					prevType, _ = m.StringToTokenType(tokens[i-1])
+				}
+				calls = append(calls, prevType == types.SingleFunction)
+				valid = true
+			case tokenType == types.SingleFunction:
+				valid = true
+			case tokenType == types.Operator && fixity == types.Prefix:
+				valid = true
+			}
+		} else {
+			switch {
+			case tokenType == types.Operator && fixity == types.Infix:
+				valid, expectOperand = true, true
+			case tokenType == types.Comma:
+				valid, expectOperand = len(calls) > 0 && calls[len(calls)-1], true
+			case tokenType == types.Operator && fixity == types.Postfix:
+				valid = true
+			case tokenType == types.RParen:
+				if len(calls) > 0 {
+					calls = calls[:len(calls)-1]
+				}
+				valid = true
+			}
+		}
+		if !valid {
+			return false, currentCharLength, i
+		}
+		currentCharLength += len(t)
 	}
-	if prevTokenType == types.Operator || prevTokenType == types.RParen {
-		return false, currentCharLength
+
+	if expectOperand && len(tokens) > 0 {
+		currentCharLength -= len(tokens[len(tokens)-1])
+		return false, currentCharLength, -1
 	}
-	currentCharLength += len(tokens[0])
+	return true, currentCharLength, -1
+}
 
-	for i := 1; i < len(tokens); i++ {
-		tokenType, _ := m.StringToTokenType(tokens[i])
-		// Whether the previous token could be the end of an expression
-		prevIsEndable := prevTokenType == types.Value || prevTokenType == types.Variable || prevTokenType == types.RParen
-		// Whether the current token can take place after an endable previous token
-		currFollows := tokenType == types.Operator || tokenType == types.RParen
-		if (prevIsEndable && !currFollows) || (!prevIsEndable && currFollows) {
-			return false, currentCharLength
+// Returns true if all tokens classified as a variable match one of the given variable names,
+// or a variable bound at their position.
+func areTokensValid[T any](tokens []string, variableNames []string, bound []boundVariable, m *types.MathGroup[T]) (bool, string) {
+	for i, t := range tokens {
+		tokenType, _ := m.StringToTokenType(t)
+		if tokenType == types.Variable && indexOf(variableNames, t) < 0 && !isBound(bound, i, t) {
+			return false, t
 		}
+	}
+	return true, ""
+}
 
-		currentCharLength += len(tokens[i])
+// A variable bound by a call to a keyword, such as k in sum(k, 1, n, k^2) or v in map(v, v^2, list).
+// It is bound at its own position, and within the body of the call.
+type boundVariable struct {
+	name  string
+	at    int // The position of the variable
+	start int // The position of the first token of the body
+	end   int // The position after the last token of the body
+}
 
-		prevTokenType = tokenType
-	}
+// Returns the variables bound by each call to a keyword that binds variables.
+func boundVariables[T any](tokens []string, m *types.MathGroup[T]) ([]boundVariable, error) {
+	bound := []boundVariable{}
+	for i, t := range tokens {
+		tokenType, keyword := m.StringToTokenType(t)
+		variables, body := m.Binding(keyword)
+		if tokenType != types.SingleFunction || variables == 0 {
+			continue
+		}
+		valid := i+1 < len(tokens) && tokens[i+1] == "("
+		for j := 0; j < variables && valid; j++ {
+			at := i + 2 + 2*j
+			valid = at+1 < len(tokens) && tokens[at+1] == ","
+			if valid {
+				variableType, _ := m.StringToTokenType(tokens[at])
+				valid = variableType == types.Variable
+			}
+		}
+		if !valid && variables == 1 {
+			return nil, errors.New("the first argument of " + t + " must be a variable")
+		} else if !valid {
+			return nil, errors.New("the first " + strconv.Itoa(variables) + " arguments of " + t + " must be variables")
+		}
 
-	validEnd := prevTokenType == types.Value || prevTokenType == types.Variable || prevTokenType == types.RParen
-	if !validEnd {
-		currentCharLength -= len(tokens[len(tokens)-1])
+		// Find the body, between the commas separating it from the other arguments
+		start, end := len(tokens), len(tokens)
+		depth, commas := 0, 0
+		for j := i + 1; j < len(tokens); j++ {
+			if tokens[j] == "(" {
+				depth++
+			} else if tokens[j] == ")" {
+				depth--
+			} else if tokens[j] == "," && depth == 1 {
+				commas++
+				if commas == body {
+					start = j + 1
+				} else if commas == body+1 {
+					end = j
+					break
+				}
+			}
+			if depth == 0 {
+				end = j
+				break
+			}
+		}
+		for j := 0; j < variables; j++ {
+			bound = append(bound, boundVariable{name: tokens[i+2+2*j], at: i + 2 + 2*j, start: start, end: end})
+		}
 	}
+	return bound, nil
+}
 
-	return validEnd, currentCharLength
+// Returns true if the variable at the given position is bound there.
+func isBound(bound []boundVariable, position int, name string) bool {
+	for _, variable := range bound {
+		if variable.name == name && (position == variable.at || position >= variable.start && position < variable.end) {
+			return true
+		}
+	}
+	return false
 }
 
-// Returns true if all tokens classified as a variable match the given variable namea variable match the given variable name.
-func areTokensValid[T any](tokens []string, variableName string, m *types.MathGroup[T]) (bool, string) {
-	for _, t := range tokens {
+// Variables returns the distinct tokens of the expression that would be parsed as variables,
+// in order of first appearance. Variables bound by keywords, such as the indices of series, are not included.
+func Variables[T any](expression string, m *types.MathGroup[T]) ([]string, error) {
+	tokens, err := parseExpression(expression, m)
+	if err != nil {
+		return nil, err
+	}
+	bound, err := boundVariables(tokens, m)
+	if err != nil {
+		return nil, err
+	}
+	variables := []string{}
+	for i, t := range tokens {
 		tokenType, _ := m.StringToTokenType(t)
-		if tokenType == types.Variable {
-			if t != variableName {
-				return false, t
-			}
+		if tokenType == types.Variable && indexOf(variables, t) < 0 && !isBound(bound, i, t) {
+			variables = append(variables, t)
 		}
 	}
-	return true, ""
+	return variables, nil
 }
 
 // Converts an expression into a list of strings, split by token.
@@ -96,10 +251,167 @@ func parseExpression[T any](expression string, m *types.MathGroup[T]) (ParsedExp
 	tokens := ParsedExpression([]string{})
 	for i := 0; i < len(expression); {
 		tokenString, nextIndex := GetNextTokenString(expression, i, m)
-		tokens = append(tokens, tokenString)
+		if tokenString != "" {
+			tokens = append(tokens, tokenString)
+		}
 		i = nextIndex
 	}
-	return tokens, nil
+	tokens, err := expandPiecewise(tokens, m)
+	if err != nil {
+		return nil, err
+	}
+	return insertImplicitOperators(resolvePrefixOperators(tokens, m), m), nil
+}
+
+// Inserts the group's implicit operator, if it has one, between each operand and
+// an operand directly following it, such as between "3" and "km" in "3 km".
+func insertImplicitOperators[T any](tokens ParsedExpression, m *types.MathGroup[T]) ParsedExpression {
+	implicit, ok := m.ImplicitToken()
+	if !ok {
+		return tokens
+	}
+	output := ParsedExpression([]string{})
+	endsOperand := false
+	for _, t := range tokens {
+		tokenType, keyword := m.StringToTokenType(t)
+		startsOperand := tokenType == types.Value || tokenType == types.Variable ||
+			tokenType == types.LParen || tokenType == types.SingleFunction
+		if endsOperand && startsOperand {
+			output = append(output, implicit)
+		}
+		output = append(output, t)
+		switch tokenType {
+		case types.Value, types.Variable, types.RParen:
+			endsOperand = true
+		case types.Operator:
+			endsOperand = m.Fixity(keyword) == types.Postfix
+		default:
+			endsOperand = false
+		}
+	}
+	return output
+}
+
+// Replaces each operator written before an operand with the group's prefix form of it, such as
+// "-" in "2 * -x", so that it can be distinguished from the infix operator with the same symbol.
+func resolvePrefixOperators[T any](tokens ParsedExpression, m *types.MathGroup[T]) ParsedExpression {
+	expectOperand := true
+	for i, t := range tokens {
+		if expectOperand {
+			t = m.PrefixToken(t)
+			tokens[i] = t
+		}
+		tokenType, keyword := m.StringToTokenType(t)
+		switch tokenType {
+		case types.Value, types.Variable, types.RParen:
+			expectOperand = false
+		case types.Operator:
+			expectOperand = m.Fixity(keyword) != types.Postfix
+		default:
+			expectOperand = true
+		}
+	}
+	return tokens
+}
+
+// Rewrites each piecewise definition, such as {x^2 if x < 0; x otherwise},
+// into calls to the group's conditional keyword, such as if(x < 0, x^2, x).
+func expandPiecewise[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExpression, error) {
+	output := ParsedExpression([]string{})
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == "}" {
+			return nil, errors.New("expression has unmatched braces")
+		}
+		if tokens[i] != "{" {
+			output = append(output, tokens[i])
+			continue
+		}
+		end := matchingBrace(tokens, i)
+		if end < 0 {
+			return nil, errors.New("expression has unmatched braces")
+		}
+		inner, err := expandPiecewise(tokens[i+1:end], m)
+		if err != nil {
+			return nil, err
+		}
+		expanded, err := piecewiseToConditional(inner, m)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, expanded...)
+		i = end
+	}
+	return output, nil
+}
+
+// Returns the index of the brace closing the one at the given index, or -1.
+func matchingBrace(tokens ParsedExpression, index int) int {
+	depth := 0
+	for i := index; i < len(tokens); i++ {
+		if tokens[i] == "{" {
+			depth++
+		} else if tokens[i] == "}" {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// The word ending the last clause of a piecewise definition
+const otherwise = "otherwise"
+
+// Converts the clauses of a piecewise definition, without its braces, into a conditional.
+// Each clause but the last separates its value from its condition with the group's conditional token,
+// so that piecewise definitions are only supported by groups with a conditional keyword.
+func piecewiseToConditional[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExpression, error) {
+	conditional, ok := m.ConditionalToken()
+	if !ok {
+		return nil, errors.New("piecewise definitions are not supported by this group")
+	}
+	clauses := [][]string{{}}
+	for _, t := range tokens {
+		if t == ";" {
+			clauses = append(clauses, []string{})
+		} else {
+			clauses[len(clauses)-1] = append(clauses[len(clauses)-1], t)
+		}
+	}
+
+	last := clauses[len(clauses)-1]
+	if len(last) < 2 || last[len(last)-1] != otherwise {
+		return nil, errors.New("piecewise definition must end with an otherwise clause")
+	}
+	output := ParsedExpression(append([]string{"("}, last[:len(last)-1]...))
+	output = append(output, ")")
+	for i := len(clauses) - 2; i >= 0; i-- {
+		split := -1
+		for j, t := range clauses[i] {
+			// A conditional token that is not followed by "(" separates the value from the condition.
+			if t == conditional && (j+1 == len(clauses[i]) || clauses[i][j+1] != "(") {
+				split = j
+				break
+			}
+		}
+		if split <= 0 || split == len(clauses[i])-1 {
+			return nil, errors.New("piecewise clause must be of the form <value> " + conditional + " <condition>")
+		}
+		clause := append([]string{conditional, "("}, clauses[i][split+1:]...)
+		clause = append(clause, ",")
+		clause = append(clause, clauses[i][:split]...)
+		clause = append(clause, ",")
+		clause = append(clause, output...)
+		output = append(clause, ")")
+	}
+	return output, nil
+}
+
+// A left parenthesis on the operator stack, and the number of arguments found within it.
+type openParen struct {
+	call     bool // Whether the parentheses surround the arguments of a function
+	argCount int
 }
 
 // ToPostfix converts a ParsedExpression from infix notation to postfix notation.
@@ -107,8 +419,31 @@ func parseExpression[T any](expression string, m *types.MathGroup[T]) (ParsedExp
 func ToPostfix[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExpression, error) {
 	output := ParsedExpression([]string{})
 	operations := stack.New()
+	parens := stack.New()
 
-	for _, t := range tokens {
+	// Pops operations into the output until a left parenthesis, or an operation the given operator
+	// has a higher priority than, is at the top of the stack.
+	popOperations := func(keyword types.Keyword) {
+		for operations.Size() > 0 {
+			prevType, prevKeyword := m.StringToTokenType(operations.Top().(string))
+			if prevType == types.LParen || m.HasHigherPriority(keyword, prevKeyword, prevType) {
+				break
+			}
+			output = append(output, operations.Pop().(string))
+		}
+	}
+	// Pops operations into the output until the matching left parenthesis is at the top of the stack.
+	popUntilParen := func() error {
+		for operations.Size() > 0 {
+			if prevType, _ := m.StringToTokenType(operations.Top().(string)); prevType == types.LParen {
+				return nil
+			}
+			output = append(output, operations.Pop().(string))
+		}
+		return errors.New("expression has unmatched parentheses")
+	}
+
+	for i, t := range tokens {
 		tokenType, keyword := m.StringToTokenType(t)
 		switch tokenType {
 		case types.Value:
@@ -116,31 +451,49 @@ func ToPostfix[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExp
 		case types.Variable:
 			output = append(output, t)
 		case types.SingleFunction:
+			if m.Arity(keyword) != 1 && (i+1 == len(tokens) || tokens[i+1] != "(") {
+				return nil, errors.New("function " + t + " must be called with parentheses")
+			}
 			operations.Push(t)
 		case types.Operator:
-			for operations.Size() > 0 {
-				prevType, prevKeyWord := m.StringToTokenType(operations.Top().(string))
-				if prevType == types.LParen || m.HasHigherPriority(keyword, prevKeyWord, prevType) {
-					break
-				}
-				output = append(output, operations.Pop().(string))
+			switch m.Fixity(keyword) {
+			case types.Prefix:
+				operations.Push(t)
+			case types.Postfix:
+				popOperations(keyword)
+				output = append(output, t)
+			default:
+				popOperations(keyword)
+				operations.Push(t)
 			}
-			operations.Push(t)
 		case types.LParen:
+			call := false
+			if i > 0 {
+				prevType, _ := m.StringToTokenType(tokens[i-1])
+				call = prevType == types.SingleFunction
+			}
 			operations.Push(t)
+			parens.Push(&openParen{call: call, argCount: 1})
+		case types.Comma:
+			if parens.Size() == 0 || !parens.Top().(*openParen).call {
+				return nil, errors.New("expression has a comma outside of a function call")
+			}
+			if err := popUntilParen(); err != nil {
+				return nil, err
+			}
+			parens.Top().(*openParen).argCount++
 		case types.RParen:
-			foundMatchingParen := false
-			for operations.Size() > 0 {
-				prevTokenString := operations.Pop().(string)
-				prevType, _ := m.StringToTokenType(prevTokenString)
-				if prevType == types.LParen {
-					foundMatchingParen = true
-					break
-				}
-				output = append(output, prevTokenString)
+			if err := popUntilParen(); err != nil {
+				return nil, err
 			}
-			if !foundMatchingParen {
-				return nil, errors.New("expression has unmatched parentheses")
+			operations.Pop()
+			paren := parens.Pop().(*openParen)
+			if paren.call {
+				function := operations.Top().(string)
+				if _, keyword := m.StringToTokenType(function); m.Arity(keyword) != paren.argCount {
+					return nil, errors.New("function " + function + " expects " + strconv.Itoa(m.Arity(keyword)) +
+						" arguments, but got " + strconv.Itoa(paren.argCount))
+				}
 			}
 		}
 	}
@@ -148,7 +501,6 @@ func ToPostfix[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExp
 		prevTokenString := operations.Pop().(string)
 		prevType, _ := m.StringToTokenType(prevTokenString)
 		if prevType == types.LParen {
-			println("Bye")
 			return nil, errors.New("expression has unmatched parentheses")
 		}
 		output = append(output, prevTokenString)
@@ -160,15 +512,28 @@ func ToPostfix[T any](tokens ParsedExpression, m *types.MathGroup[T]) (ParsedExp
 // Parse takes in the expression given, and parses it into in postfix form.
 // This expression can be used in the evaluate module.
 func Parse[T any](expression string, variableName string, m *types.MathGroup[T]) (ParsedExpression, error) {
+	return ParseVars(expression, []string{variableName}, m)
+}
+
+// ParseVars is like Parse, but allows the expression to use any of the given variable names.
+func ParseVars[T any](expression string, variableNames []string, m *types.MathGroup[T]) (ParsedExpression, error) {
 	tokens, err := parseExpression(expression, m)
 	if err != nil {
 		return nil, err
 	}
-	if valid, t := areTokensValid(tokens, variableName, m); !valid {
+	bound, err := boundVariables(tokens, m)
+	if err != nil {
+		return nil, err
+	}
+	if valid, t := areTokensValid(tokens, variableNames, bound, m); !valid {
 		return nil, errors.New("Token " + t + " is not recognized.")
 	}
-	if isValid, i := IsLocallyValid(tokens, m); !isValid {
-		return nil, errors.New("Expression is not valid\n" + expression + "\n" + strings.Repeat(" ", i) + "^")
+	if isValid, i, invalid := checkLocally(tokens, m); !isValid {
+		message := "Expression is not valid"
+		if invalid >= 0 && tokens[invalid] == "," {
+			message = "expression has a comma outside of a function call"
+		}
+		return nil, errors.New(message + "\n" + expression + "\n" + strings.Repeat(" ", i) + "^")
 	}
 	finalExpr, err := ToPostfix(tokens, m)
 	if err != nil {
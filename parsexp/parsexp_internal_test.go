@@ -7,8 +7,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/yasteen/go-parse/mathgroups/rational"
 	"github.com/yasteen/go-parse/mathgroups/real"
 	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
 )
 
 func testGetNextTokenStringHelper(input string, expected []string, t *testing.T) {
@@ -28,6 +30,11 @@ func testGetNextTokenStringHelper(input string, expected []string, t *testing.T)
 func TestGetNextTokenWithString(t *testing.T) {
 	testGetNextTokenStringHelper("sin(x)", []string{"sin", "(", "x", ")"}, t)
 	testGetNextTokenStringHelper("x * log((5 +3) / 2)", []string{"x", "*", "log", "(", "(", "5", "+", "3", ")", "/", "2", ")"}, t)
+	testGetNextTokenStringHelper("x<=-1&&x!=2", []string{"x", "<=", "-", "1", "&&", "x", "!=", "2"}, t)
+	testGetNextTokenStringHelper("{x if x>1; 0 otherwise}", []string{"{", "x", "if", "x", ">", "1", ";", "0", "otherwise", "}"}, t)
+	testGetNextTokenStringHelper("[1, 2; 3, 4]*x", []string{"[1, 2; 3, 4]", "*", "x"}, t)
+	testGetNextTokenStringHelper("to(x, \"km/h\")", []string{"to", "(", "x", ",", "\"km/h\"", ")"}, t)
+	testGetNextTokenStringHelper("x+2∠45°", []string{"x", "+", "2∠45", "°"}, t)
 }
 
 func testIsLocallyValidHelper(input []string, expected bool, t *testing.T) {
@@ -50,6 +57,8 @@ func TestIsLocallyValid(t *testing.T) {
 	testIsLocallyValidHelper([]string{"sin", "x"}, true, t)
 	testIsLocallyValidHelper([]string{"1", "+", "3", "*", "sin", "y"}, true, t)
 	testIsLocallyValidHelper([]string{"(", "x", "^", "2", "-", "9", ")", "+", "x"}, true, t)
+	testIsLocallyValidHelper([]string{"x", "!", "%"}, true, t)
+	testIsLocallyValidHelper([]string{"not", "not", "(", "x", ")", "!"}, true, t)
 
 	testIsLocallyValidHelper([]string{"("}, false, t)
 	testIsLocallyValidHelper([]string{")"}, false, t)
@@ -57,6 +66,21 @@ func TestIsLocallyValid(t *testing.T) {
 	testIsLocallyValidHelper([]string{"sin"}, false, t)
 	testIsLocallyValidHelper([]string{"sin", "+", "x"}, false, t)
 	testIsLocallyValidHelper([]string{"4", "^"}, false, t)
+	testIsLocallyValidHelper([]string{"!", "x"}, false, t)
+	testIsLocallyValidHelper([]string{"x", "not"}, false, t)
+	testIsLocallyValidHelper([]string{"x", ",", "x"}, false, t)
+	testIsLocallyValidHelper([]string{"(", "x", ",", "x", ")"}, false, t)
+	testIsLocallyValidHelper([]string{"sin", "(", "(", "x", ",", "x", ")", ")"}, false, t)
+	testIsLocallyValidHelper([]string{"if", "(", "x", ",", "(", "x", ")", ",", "x", ")"}, true, t)
+
+	for _, expression := range []string{"x, x", "(x, x)", "sin((x, x))"} {
+		if _, err := parsexp.Parse(expression, "x", real.Real); err == nil || !strings.HasPrefix(err.Error(), "expression has a comma outside of a function call") {
+			t.Error("Expected a comma error on", expression, "Result:", err)
+		}
+	}
+	if _, err := parsexp.ToPostfix([]string{"x", ",", "x"}, real.Real); err == nil || err.Error() != "expression has a comma outside of a function call" {
+		t.Error("Expected a comma error from ToPostfix. Result:", err)
+	}
 }
 
 func testToPostfixHelper(input []string, expected []string, unmatchedParen bool) error {
@@ -102,4 +126,103 @@ func TestToPostfixHelper(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
+	err = testToPostfixHelper([]string{"2", "^", "x", "!", "%"}, []string{"2", "x", "!", "%", "^"}, false)
+	if err != nil {
+		t.Error(err)
+	}
+	err = testToPostfixHelper([]string{"not", "x", "+", "1", "*", "x"}, []string{"x", "1", "x", "*", "+", "not"}, false)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func testToInfixHelper(input string, style parsexp.ParenStyle, expected string, t *testing.T) {
+	parsed, err := parsexp.Parse(input, "x", real.Real)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	output, err := parsexp.ToInfix(parsed, real.Real, style)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if output != expected {
+		t.Errorf("ToInfix failed on '%s'. Expected '%s', got '%s'", input, expected, output)
+	}
+}
+
+func TestToInfix(t *testing.T) {
+	testToInfixHelper("x", parsexp.MinimalParens, "x", t)
+	testToInfixHelper("(x - 1) - (x - 2.50)", parsexp.MinimalParens, "x - 1 - (x - 2.5)", t)
+	testToInfixHelper("2^3^x", parsexp.MinimalParens, "2 ^ 3 ^ x", t)
+	testToInfixHelper("(2^3)^x", parsexp.MinimalParens, "2 ^ 3 ^ x", t)
+	testToInfixHelper("2^(3^x)", parsexp.MinimalParens, "2 ^ (3 ^ x)", t)
+	testToInfixHelper("sin((x)) * (3 + x) / x", parsexp.MinimalParens, "sin(x) * (3 + x) / x", t)
+	testToInfixHelper("x + 2 * x ^ 2", parsexp.FullParens, "x + (2 * (x ^ 2))", t)
+	testToInfixHelper("(x ^ 2)! + x%", parsexp.MinimalParens, "(x ^ 2)! + x%", t)
+	testToInfixHelper("not (x + 1)", parsexp.MinimalParens, "not x + 1", t)
+	testToInfixHelper("-x^2 + -(x+1)", parsexp.MinimalParens, "-x ^ 2 + -(x + 1)", t)
+	testToInfixHelper("{x if x < 0; 0 otherwise}", parsexp.MinimalParens, "if(x < 0, x, 0)", t)
+	testToInfixHelper("sum(k, 1, x, k^2)", parsexp.MinimalParens, "sum(k, 1, x, k ^ 2)", t)
+	testToInfixHelper("-(x*x)", parsexp.MinimalParens, "-(x * x)", t)
+	testToInfixHelper("-x * x", parsexp.MinimalParens, "-x * x", t)
+
+	for _, input := range []string{"-(x*y)", "-(x/y)", "-x*y", "x! * -(x/y)"} {
+		parsed, err := parsexp.ParseVars(input, []string{"x", "y"}, real.Real)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, err := parsexp.ToInfix(parsed, real.Real, parsexp.MinimalParens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reparsed, err := parsexp.ParseVars(output, []string{"x", "y"}, real.Real)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(reparsed, " ") != strings.Join(parsed, " ") {
+			t.Errorf("ToInfix round trip failed on '%s'. Printed '%s', which parses to %v instead of %v", input, output, reparsed, parsed)
+		}
+	}
+}
+
+func TestVariables(t *testing.T) {
+	variables, err := parsexp.Variables("sum(k, 1, n, k * x) + k", real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "n x k"; strings.Join(variables, " ") != expected {
+		t.Errorf("Variables failed. Expected '%s', got '%s'", expected, strings.Join(variables, " "))
+	}
+}
+
+func TestPiecewiseConditionalToken(t *testing.T) {
+	const when types.Keyword = iota
+	group := types.NewMathGroup(
+		map[types.Keyword]types.KeywordData[float64]{
+			when: {Symbol: "when", TokenType: types.SingleFunction, Arity: 3,
+				Condition: func(param float64) bool { return param != 0 },
+				Apply:     types.Infallible(func(params ...float64) float64 { return params[1] })},
+		},
+		map[string]types.Keyword{"when": when},
+		map[types.Keyword]int{},
+		func(s string) (float64, bool) {
+			value, err := strconv.ParseFloat(s, 64)
+			return value, err == nil
+		},
+	)
+	parsed, err := parsexp.Parse("{1 when x; 0 otherwise}", "x", group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(parsed, " ") != "x 1 0 when" {
+		t.Error("Piecewise failed. Expected 'x 1 0 when'. Result:", parsed)
+	}
+	if _, err := parsexp.Parse("{1 if x; 0 otherwise}", "x", group); err == nil {
+		t.Error("Expected an error for a clause without the group's conditional token")
+	}
+	if _, err := parsexp.Parse("{1 if x > 0; 0 otherwise}", "x", rational.Rational); err == nil {
+		t.Error("Expected an error for a piecewise definition in a group without a conditional")
+	}
 }
@@ -0,0 +1,129 @@
+package parsexp
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/karalabe/cookiejar/collections/stack"
+	"github.com/yasteen/go-parse/types"
+)
+
+// ParenStyle determines how many parentheses are used when printing an expression.
+type ParenStyle int
+
+// The possible parenthesis styles
+const (
+	MinimalParens ParenStyle = iota // Only where precedence and associativity require them
+	FullParens                      // Around every operation
+)
+
+// A printed subexpression, along with the precedence of its outermost operator.
+type printedTerm struct {
+	text       string
+	precedence int
+}
+
+const atomPrecedence = math.MaxInt
+
+// ToInfix converts a ParsedExpression in postfix notation back into readable infix notation.
+// Values are normalized with the group's FormatValue.
+func ToInfix[T any](expression ParsedExpression, m *types.MathGroup[T], style ParenStyle) (string, error) {
+	terms := stack.New()
+	pop := func() (printedTerm, error) {
+		if terms.Size() == 0 {
+			return printedTerm{}, errors.New("expression is invalid")
+		}
+		return terms.Pop().(printedTerm), nil
+	}
+
+	for _, t := range expression {
+		tokenType, keyword := m.StringToTokenType(t)
+		switch tokenType {
+		case types.Value:
+			terms.Push(printValue(t, m))
+		case types.Variable:
+			terms.Push(printedTerm{t, atomPrecedence})
+		case types.SingleFunction:
+			args := make([]string, m.Arity(keyword))
+			for i := len(args) - 1; i >= 0; i-- {
+				arg, err := pop()
+				if err != nil {
+					return "", err
+				}
+				args[i] = arg.text
+			}
+			terms.Push(printedTerm{m.KeywordToString(keyword) + "(" + strings.Join(args, ", ") + ")", atomPrecedence})
+		case types.Operator:
+			precedence := m.Precedence(keyword)
+			full := style == FullParens
+			symbol := m.KeywordToString(keyword)
+			if m.Fixity(keyword) != types.Infix {
+				operand, err := pop()
+				if err != nil {
+					return "", err
+				}
+				// A prefix operator is read before the operations of its operand, so an operand of equal
+				// precedence needs parentheses, as in -(x * y).
+				prefix := m.Fixity(keyword) == types.Prefix
+				if (full && operand.precedence != atomPrecedence) || operand.precedence < precedence || (prefix && operand.precedence == precedence) {
+					operand.text = "(" + operand.text + ")"
+				}
+				if m.Fixity(keyword) == types.Postfix {
+					terms.Push(printedTerm{operand.text + symbol, precedence})
+				} else if isWord(symbol) {
+					terms.Push(printedTerm{symbol + " " + operand.text, precedence})
+				} else {
+					terms.Push(printedTerm{symbol + operand.text, precedence})
+				}
+				continue
+			}
+			right, err := pop()
+			if err != nil {
+				return "", err
+			}
+			left, err := pop()
+			if err != nil {
+				return "", err
+			}
+			leftAssociative := m.Associativity(keyword) == types.LeftAssociative
+			if (full && left.precedence != atomPrecedence) || needsParens(left.precedence, precedence, !leftAssociative) {
+				left.text = "(" + left.text + ")"
+			}
+			if (full && right.precedence != atomPrecedence) || needsParens(right.precedence, precedence, leftAssociative) {
+				right.text = "(" + right.text + ")"
+			}
+			terms.Push(printedTerm{left.text + " " + symbol + " " + right.text, precedence})
+		default:
+			return "", errors.New("invalid token " + t)
+		}
+	}
+	if terms.Size() != 1 {
+		return "", errors.New("expression is invalid")
+	}
+	return terms.Pop().(printedTerm).text, nil
+}
+
+// Returns true if an operand must be parenthesized. Operands of equal precedence
+// need parentheses on the side the operator does not associate towards.
+func needsParens(operand int, operator int, againstAssociativity bool) bool {
+	return operand < operator || (operand == operator && againstAssociativity)
+}
+
+// Returns true if the symbol ends in a letter or digit, and so must be separated from its operand.
+func isWord(symbol string) bool {
+	last := symbol[len(symbol)-1]
+	return last == '_' || unicode.IsLetter(rune(last)) || unicode.IsDigit(rune(last))
+}
+
+// Formats a value token. If the formatted value would be read back as several tokens
+// (such as "3 - 2i"), it is treated like an operation of the lowest precedence.
+func printValue[T any](t string, m *types.MathGroup[T]) printedTerm {
+	value, _ := m.GetValue(t)
+	text := m.FormatValue(value)
+	if tokens, _ := parseExpression(text, m); len(tokens) != 1 {
+		return printedTerm{text, math.MinInt}
+	}
+	return printedTerm{text, atomPrecedence}
+}
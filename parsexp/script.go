@@ -0,0 +1,211 @@
+package parsexp
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// Binding is a statement of a script that binds a name to the value of an expression.
+type Binding struct {
+	Name       string
+	Lets       []Binding        // Names bound with let, in order, which only this statement can use
+	Expression ParsedExpression // In postfix notation
+}
+
+// Script is a parsed script: bindings that are evaluated in order, and an expression giving its result.
+type Script struct {
+	Bindings []Binding
+	Lets     []Binding        // Names bound with let, in order, which only the result can use
+	Result   ParsedExpression // In postfix notation
+}
+
+// Returns the script without its comments, which run from # to the end of the line,
+// and with each newline or tab replaced by a space.
+func stripComments(script string) string {
+	var output strings.Builder
+	quoted, comment := false, false
+	for _, r := range script {
+		switch {
+		case r == '\n':
+			comment = false
+		case comment:
+			continue
+		case r == '"':
+			quoted = !quoted
+		case r == '#' && !quoted:
+			comment = true
+			continue
+		}
+		if unicode.IsSpace(r) {
+			r = ' '
+		}
+		output.WriteRune(r)
+	}
+	return output.String()
+}
+
+// Splits a script into statements at each semicolon outside of brackets, braces, parentheses and quotes.
+func splitStatements(script string) []string {
+	statements := []string{}
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ';' && depth == 0:
+			statements = append(statements, script[start:i])
+			start = i + 1
+		}
+	}
+	return append(statements, script[start:])
+}
+
+// Returns true if s is a name that can be bound, which is a letter followed by letters, digits and underscores.
+func isName(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r) && r != '_') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Splits an assignment, like "a = 2*x", into its name and expression.
+// The = must not be part of an operator such as ==, <= or !=.
+func splitAssignment(statement string) (name string, expression string, ok bool) {
+	i := strings.IndexByte(statement, '=')
+	if i < 0 || strings.HasPrefix(statement[i+1:], "=") {
+		return "", "", false
+	}
+	name = strings.TrimSpace(statement[:i])
+	return name, statement[i+1:], isName(name)
+}
+
+// Splits a statement like "let y = x^2 in y + 1" into the assignment "y = x^2" and the statement "y + 1".
+// The in that ends the assignment is the first one not matched by a nested let.
+func splitLet(statement string) (assignment string, rest string, ok bool) {
+	if words := strings.Fields(statement); len(words) == 0 || words[0] != "let" {
+		return "", "", false
+	}
+	start := strings.Index(statement, "let") + len("let")
+	lets := 0
+	for i := start; i < len(statement); i++ {
+		if isNameByte(statement[i-1]) {
+			continue
+		}
+		end := i
+		for end < len(statement) && isNameByte(statement[end]) {
+			end++
+		}
+		switch statement[i:end] {
+		case "let":
+			lets++
+		case "in":
+			if lets == 0 {
+				return statement[start:i], statement[end:], true
+			}
+			lets--
+		}
+	}
+	return "", "", false
+}
+
+// Returns true if c can be part of a name, including the bytes of multibyte characters
+func isNameByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// ParseScript parses a script of statements separated by semicolons, such as "a = 2*x; b = a^2 + 1; b / a".
+// Each statement but the last binds a name with =, and can use the given variable names and the names bound
+// before it. The last statement is an expression, giving the result of the script. A statement, or the expression
+// of a binding, can also be written "let y = x^2 in y + 1", which binds y for the rest of that statement only.
+// Comments run from # to the end of the line, and newlines are treated as spaces.
+func ParseScript[T any](script string, variableNames []string, m *types.MathGroup[T]) (*Script, error) {
+	names := append([]string{}, variableNames...)
+	var lets func(statement string) ([]Binding, string, error)
+	bind := func(assignment string) (Binding, error) {
+		name, expression, ok := splitAssignment(assignment)
+		if !ok {
+			return Binding{}, errors.New("statement " + strings.TrimSpace(assignment) + " must be of the form <name> = <expression>")
+		}
+		if tokenType, _ := m.StringToTokenType(name); tokenType != types.Variable {
+			return Binding{}, errors.New(name + " can not be bound, because it is a value or keyword")
+		}
+		scope := len(names)
+		defer func() { names = names[:scope] }()
+		expressionLets, expression, err := lets(expression)
+		if err != nil {
+			return Binding{}, err
+		}
+		postfix, err := ParseVars(expression, names, m)
+		if err != nil {
+			return Binding{}, err
+		}
+		return Binding{Name: name, Lets: expressionLets, Expression: postfix}, nil
+	}
+	// Binds the names of the lets at the start of a statement, for the rest of it, and returns the rest
+	lets = func(statement string) ([]Binding, string, error) {
+		bindings := []Binding{}
+		for {
+			assignment, rest, ok := splitLet(statement)
+			if !ok {
+				return bindings, statement, nil
+			}
+			binding, err := bind(assignment)
+			if err != nil {
+				return nil, "", err
+			}
+			bindings = append(bindings, binding)
+			names = append(names, binding.Name)
+			statement = rest
+		}
+	}
+
+	parsed := &Script{Bindings: []Binding{}}
+	statements := []string{}
+	for _, statement := range splitStatements(stripComments(script)) {
+		if strings.TrimSpace(statement) != "" {
+			statements = append(statements, statement)
+		}
+	}
+	if len(statements) == 0 {
+		return nil, errors.New("script has no expression")
+	}
+	for i, statement := range statements {
+		// Names bound by let are only kept until the end of the statement
+		scope := len(names)
+		statementLets, statement, err := lets(statement)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(statements)-1 {
+			binding, err := bind(statement)
+			if err != nil {
+				return nil, err
+			}
+			// In "let y = 2 in a = let z = 3 in y + z", y is bound before z
+			binding.Lets = append(statementLets, binding.Lets...)
+			parsed.Bindings = append(parsed.Bindings, binding)
+			names = append(names[:scope], binding.Name)
+			continue
+		}
+		if _, _, ok := splitAssignment(statement); ok {
+			return nil, errors.New("script must end with an expression, not a binding")
+		}
+		result, err := ParseVars(statement, names, m)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Lets = statementLets
+		parsed.Result = result
+	}
+	return parsed, nil
+}
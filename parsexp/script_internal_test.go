@@ -0,0 +1,55 @@
+package parsexp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/parsexp"
+)
+
+func TestParseScript(t *testing.T) {
+	script, err := parsexp.ParseScript("a = 2*x; b = a^2 + 1; # comment; c = 1\n b / a", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script.Bindings) != 2 || script.Bindings[0].Name != "a" || script.Bindings[1].Name != "b" {
+		t.Fatal("ParseScript produced the wrong bindings:", script.Bindings)
+	}
+	if output := strings.Join(script.Bindings[1].Expression, " "); output != "a 2 ^ 1 +" {
+		t.Errorf("ParseScript failed. Expected 'a 2 ^ 1 +', got '%s'", output)
+	}
+	if output := strings.Join(script.Result, " "); output != "b a /" {
+		t.Errorf("ParseScript failed. Expected 'b a /', got '%s'", output)
+	}
+
+	script, err = parsexp.ParseScript("let y = x^2 in let z = y + 1 in\n\ty * z", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script.Bindings) != 0 || len(script.Lets) != 2 || script.Lets[0].Name != "y" || script.Lets[1].Name != "z" {
+		t.Fatal("ParseScript produced the wrong bindings:", script.Bindings, script.Lets)
+	}
+
+	script, err = parsexp.ParseScript("let y = 2 in a = let z = y in y + z; a", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script.Bindings) != 1 || script.Bindings[0].Name != "a" || len(script.Bindings[0].Lets) != 2 || script.Bindings[0].Lets[1].Name != "z" {
+		t.Fatal("ParseScript produced the wrong bindings:", script.Bindings)
+	}
+	if output := strings.Join(script.Bindings[0].Expression, " "); output != "y z +" {
+		t.Errorf("ParseScript failed. Expected 'y z +', got '%s'", output)
+	}
+
+	for _, s := range []string{"x <= 1; x", "a = 1", "a = b; a", "2 = x; x", "sin = x; x", "a = 1; b", "let a = 1 a", "# only a comment", "let y = 2 in a = y; y + a", "a = let y = 2 in y; y", "let y = let z = 1 in z in z"} {
+		if _, err := parsexp.ParseScript(s, []string{"x"}, real.Real); err == nil {
+			t.Errorf("Expected an error on script '%s'", s)
+		}
+	}
+	for _, s := range []string{"x <= 1;", "a = x == 1; a != 0", "{1 if x > 0; 0 otherwise}", "let in_x = x in in_x", "a = let y = 2 in y + 1; a", "let y = let z = 1 in z in y"} {
+		if _, err := parsexp.ParseScript(s, []string{"x"}, real.Real); err != nil {
+			t.Errorf("Unexpected error on script '%s': %s", s, err)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package parsexp
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/yasteen/go-parse/types"
+)
+
+// EncodingVersion is the version of the JSON and binary encodings of an Expression.
+const EncodingVersion = 1
+
+// Magic bytes at the start of the binary encoding
+const binaryMagic = "GPX"
+
+// Kinds of encoded tokens
+const (
+	valueToken    byte = iota // A literal value
+	variableToken             // An index into the variable names
+	keywordToken              // An index into the keyword table
+	indexToken                // The name of a variable bound by a keyword, such as the index of a series
+)
+
+// Expression is a parsed expression, along with the variables and group it was parsed with.
+// It can be exchanged as JSON or in a compact binary form. When decoding,
+// Group must already be set to the receiving group, which the expression is validated against.
+type Expression[T any] struct {
+	Postfix   ParsedExpression
+	Variables []string
+	Group     *types.MathGroup[T]
+}
+
+// NewExpression parses the given expression, and binds it to its variables and group.
+func NewExpression[T any](expression string, variableNames []string, m *types.MathGroup[T]) (*Expression[T], error) {
+	postfix, err := ParseVars(expression, variableNames, m)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression[T]{Postfix: postfix, Variables: variableNames, Group: m}, nil
+}
+
+// The keyword symbols an expression uses, and the number of arguments each is applied to.
+type encodedKeyword struct {
+	Symbol string `json:"symbol"`
+	Arity  int    `json:"arity"`
+}
+
+type encodedToken struct {
+	Kind  byte   `json:"kind"`
+	Value string `json:"value,omitempty"`
+	Index int    `json:"index,omitempty"`
+}
+
+type encodedExpression struct {
+	Version   int              `json:"version"`
+	Group     string           `json:"group"`
+	Variables []string         `json:"variables"`
+	Keywords  []encodedKeyword `json:"keywords"`
+	Tokens    []encodedToken   `json:"tokens"`
+}
+
+// Converts the expression into the form shared by both encodings.
+func (e *Expression[T]) encode() (*encodedExpression, error) {
+	if e.Group == nil {
+		return nil, errors.New("expression has no group")
+	}
+	encoded := &encodedExpression{
+		Version:   EncodingVersion,
+		Group:     e.Group.Name,
+		Variables: e.Variables,
+		Keywords:  []encodedKeyword{},
+		Tokens:    []encodedToken{},
+	}
+	keywordIndices := map[string]int{}
+	bound := boundPositions(e.Postfix, e.Group)
+	for i, t := range e.Postfix {
+		tokenType, keyword := e.Group.StringToTokenType(t)
+		switch {
+		case tokenType == types.Value:
+			encoded.Tokens = append(encoded.Tokens, encodedToken{Kind: valueToken, Value: t})
+		case tokenType == types.Variable && bound[i]:
+			encoded.Tokens = append(encoded.Tokens, encodedToken{Kind: indexToken, Value: t})
+		case tokenType == types.Variable:
+			index := indexOf(e.Variables, t)
+			if index < 0 {
+				return nil, errors.New("token " + t + " is not recognized")
+			}
+			encoded.Tokens = append(encoded.Tokens, encodedToken{Kind: variableToken, Index: index})
+		case tokenType == types.Operator || tokenType == types.SingleFunction:
+			index, ok := keywordIndices[t]
+			if !ok {
+				index = len(encoded.Keywords)
+				keywordIndices[t] = index
+				encoded.Keywords = append(encoded.Keywords, encodedKeyword{Symbol: t, Arity: e.Group.Arity(keyword)})
+			}
+			encoded.Tokens = append(encoded.Tokens, encodedToken{Kind: keywordToken, Index: index})
+		default:
+			return nil, errors.New("expression is not in postfix notation")
+		}
+	}
+	return encoded, nil
+}
+
+// Converts an encoded expression back, validating it against the expression's group.
+func (e *Expression[T]) decode(encoded *encodedExpression) error {
+	if e.Group == nil {
+		return errors.New("expression has no group to decode into")
+	}
+	if encoded.Version != EncodingVersion {
+		return errors.New("unsupported encoding version " + strconv.Itoa(encoded.Version))
+	}
+	if encoded.Group != e.Group.Name {
+		return errors.New("expression was parsed in group " + encoded.Group + ", not " + e.Group.Name)
+	}
+	for _, v := range encoded.Variables {
+		if tokenType, _ := e.Group.StringToTokenType(v); tokenType != types.Variable {
+			return errors.New("variable " + v + " is not a valid variable name")
+		}
+	}
+	for _, k := range encoded.Keywords {
+		tokenType, keyword := e.Group.StringToTokenType(k.Symbol)
+		if tokenType != types.Operator && tokenType != types.SingleFunction {
+			return errors.New("keyword " + k.Symbol + " is not recognized")
+		}
+		if e.Group.Arity(keyword) != k.Arity {
+			return errors.New("keyword " + k.Symbol + " takes " + strconv.Itoa(e.Group.Arity(keyword)) +
+				" arguments, not " + strconv.Itoa(k.Arity))
+		}
+	}
+
+	postfix := ParsedExpression([]string{})
+	depth := 0
+	for _, t := range encoded.Tokens {
+		switch t.Kind {
+		case valueToken:
+			if tokenType, _ := e.Group.StringToTokenType(t.Value); tokenType != types.Value {
+				return errors.New("value " + t.Value + " is not recognized")
+			}
+			postfix = append(postfix, t.Value)
+			depth++
+		case variableToken:
+			if t.Index < 0 || t.Index >= len(encoded.Variables) {
+				return errors.New("variable index out of range")
+			}
+			postfix = append(postfix, encoded.Variables[t.Index])
+			depth++
+		case indexToken:
+			if tokenType, _ := e.Group.StringToTokenType(t.Value); tokenType != types.Variable {
+				return errors.New("index " + t.Value + " is not a valid variable name")
+			}
+			postfix = append(postfix, t.Value)
+			depth++
+		case keywordToken:
+			if t.Index < 0 || t.Index >= len(encoded.Keywords) {
+				return errors.New("keyword index out of range")
+			}
+			keyword := encoded.Keywords[t.Index]
+			if depth < keyword.Arity {
+				return errors.New("expression is invalid")
+			}
+			postfix = append(postfix, keyword.Symbol)
+			depth += 1 - keyword.Arity
+		default:
+			return errors.New("unknown token kind " + strconv.Itoa(int(t.Kind)))
+		}
+	}
+	if depth != 1 {
+		return errors.New("expression is invalid")
+	}
+
+	e.Postfix = postfix
+	e.Variables = encoded.Variables
+	return nil
+}
+
+// MarshalJSON encodes the expression as JSON.
+func (e *Expression[T]) MarshalJSON() ([]byte, error) {
+	encoded, err := e.encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes an expression from JSON, validating it against the expression's group.
+func (e *Expression[T]) UnmarshalJSON(data []byte) error {
+	encoded := &encodedExpression{}
+	if err := json.Unmarshal(data, encoded); err != nil {
+		return err
+	}
+	return e.decode(encoded)
+}
+
+// MarshalBinary encodes the expression in a compact binary form.
+// Strings and numbers are written as uvarint lengths and values.
+func (e *Expression[T]) MarshalBinary() ([]byte, error) {
+	encoded, err := e.encode()
+	if err != nil {
+		return nil, err
+	}
+	data := append([]byte(binaryMagic), byte(encoded.Version))
+	writeString := func(s string) {
+		data = appendUvarint(data, uint64(len(s)))
+		data = append(data, s...)
+	}
+
+	writeString(encoded.Group)
+	data = appendUvarint(data, uint64(len(encoded.Variables)))
+	for _, v := range encoded.Variables {
+		writeString(v)
+	}
+	data = appendUvarint(data, uint64(len(encoded.Keywords)))
+	for _, k := range encoded.Keywords {
+		writeString(k.Symbol)
+		data = appendUvarint(data, uint64(k.Arity))
+	}
+	data = appendUvarint(data, uint64(len(encoded.Tokens)))
+	for _, t := range encoded.Tokens {
+		data = append(data, t.Kind)
+		if t.Kind == valueToken || t.Kind == indexToken {
+			writeString(t.Value)
+		} else {
+			data = appendUvarint(data, uint64(t.Index))
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes an expression from its binary form, validating it against the expression's group.
+func (e *Expression[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errors.New("data is not an encoded expression")
+	}
+	encoded := &encodedExpression{Version: int(data[len(binaryMagic)])}
+	if encoded.Version != EncodingVersion {
+		return errors.New("unsupported encoding version " + strconv.Itoa(encoded.Version))
+	}
+	data = data[len(binaryMagic)+1:]
+
+	var err error
+	readUint := func() int {
+		n, size := binary.Uvarint(data)
+		if size <= 0 || n > math.MaxInt32 {
+			err = errors.New("encoded expression is truncated")
+			return 0
+		}
+		data = data[size:]
+		return int(n)
+	}
+	// Reads a number of items or bytes, each of which takes at least one more byte
+	readCount := func() int {
+		n := readUint()
+		if err != nil || n > len(data) {
+			err = errors.New("encoded expression is truncated")
+			return 0
+		}
+		return n
+	}
+	readString := func() string {
+		n := readCount()
+		if err != nil {
+			return ""
+		}
+		s := string(data[:n])
+		data = data[n:]
+		return s
+	}
+
+	encoded.Group = readString()
+	encoded.Variables = make([]string, readCount())
+	for i := 0; i < len(encoded.Variables) && err == nil; i++ {
+		encoded.Variables[i] = readString()
+	}
+	encoded.Keywords = make([]encodedKeyword, readCount())
+	for i := 0; i < len(encoded.Keywords) && err == nil; i++ {
+		encoded.Keywords[i] = encodedKeyword{Symbol: readString(), Arity: readUint()}
+	}
+	encoded.Tokens = make([]encodedToken, readCount())
+	for i := 0; i < len(encoded.Tokens) && err == nil; i++ {
+		if len(data) == 0 {
+			return errors.New("encoded expression is truncated")
+		}
+		encoded.Tokens[i].Kind = data[0]
+		data = data[1:]
+		if kind := encoded.Tokens[i].Kind; kind == valueToken || kind == indexToken {
+			encoded.Tokens[i].Value = readString()
+		} else {
+			encoded.Tokens[i].Index = readUint()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) != 0 {
+		return errors.New("encoded expression has trailing data")
+	}
+	return e.decode(encoded)
+}
+
+// Returns the positions of the variables in a postfix expression that are bound by keywords, such as series,
+// which are the variables themselves, and their uses within the body they are bound in.
+func boundPositions[T any](postfix ParsedExpression, m *types.MathGroup[T]) map[int]bool {
+	bound := map[int]bool{}
+	// The position of the first token of each operand on the stack
+	starts := []int{}
+	for i, t := range postfix {
+		tokenType, keyword := m.StringToTokenType(t)
+		arity := 0
+		if tokenType == types.Operator || tokenType == types.SingleFunction {
+			arity = m.Arity(keyword)
+		}
+		if arity > len(starts) {
+			return bound
+		}
+		args := append(starts[len(starts)-arity:], i)
+		variables, body := m.Binding(keyword)
+		for j := 0; j < variables; j++ {
+			if args[j+1] != args[j]+1 {
+				continue
+			}
+			bound[args[j]] = true
+			for k := args[body]; k < args[body+1]; k++ {
+				if postfix[k] == postfix[args[j]] {
+					bound[k] = true
+				}
+			}
+		}
+		start := i
+		if arity > 0 {
+			start = args[0]
+		}
+		starts = append(starts[:len(starts)-arity], start)
+	}
+	return bound
+}
+
+func appendUvarint(data []byte, n uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(data, buf[:binary.PutUvarint(buf, n)]...)
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,116 @@
+package parsexp_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yasteen/go-parse/mathgroups/complex"
+	"github.com/yasteen/go-parse/mathgroups/decimal"
+	"github.com/yasteen/go-parse/mathgroups/real"
+	"github.com/yasteen/go-parse/parsexp"
+	"github.com/yasteen/go-parse/types"
+)
+
+func testSameExpression(expected *parsexp.Expression[float64], output *parsexp.Expression[float64], t *testing.T) {
+	if strings.Join(output.Postfix, " ") != strings.Join(expected.Postfix, " ") {
+		t.Error("Decoded expression does not match. Expected", expected.Postfix, "Produced", output.Postfix)
+	}
+	if strings.Join(output.Variables, " ") != strings.Join(expected.Variables, " ") {
+		t.Error("Decoded variables do not match. Expected", expected.Variables, "Produced", output.Variables)
+	}
+}
+
+func TestExpressionJSON(t *testing.T) {
+	expression, err := parsexp.NewExpression("if(x < y, -x, sin(y) ^ 2)", []string{"x", "y"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &parsexp.Expression[float64]{Group: real.Real}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	testSameExpression(expression, decoded, t)
+
+	if err := json.Unmarshal(data, &parsexp.Expression[complex.Number]{Group: complex.Complex}); err == nil {
+		t.Error("Failed to detect mismatched group.")
+	}
+	tampered := strings.Replace(string(data), `"arity":3`, `"arity":2`, 1)
+	if err := json.Unmarshal([]byte(tampered), &parsexp.Expression[float64]{Group: real.Real}); err == nil {
+		t.Error("Failed to detect mismatched keyword.")
+	}
+}
+
+func TestExpressionBinary(t *testing.T) {
+	expression, err := parsexp.NewExpression("x * 2.5 + x!", []string{"x"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := expression.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &parsexp.Expression[float64]{Group: real.Real}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	testSameExpression(expression, decoded, t)
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Failed to detect truncated data.")
+	}
+	if err := (&parsexp.Expression[complex.Number]{Group: complex.Complex}).UnmarshalBinary(data); err == nil {
+		t.Error("Failed to detect mismatched group.")
+	}
+}
+
+func TestExpressionSeries(t *testing.T) {
+	expression, err := parsexp.NewExpression("sum(k, 1, n, x^k)", []string{"x", "n"}, real.Real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := expression.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &parsexp.Expression[float64]{Group: real.Real}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	testSameExpression(expression, decoded, t)
+}
+
+func TestExpressionGroupParameters(t *testing.T) {
+	expression, err := parsexp.NewExpression("x * 1.555", []string{"x"}, decimal.New(3, decimal.HalfEven))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &parsexp.Expression[decimal.Decimal]{Group: decimal.New(3, decimal.HalfEven)}); err != nil {
+		t.Error(err)
+	}
+	if err := json.Unmarshal(data, &parsexp.Expression[decimal.Decimal]{Group: decimal.Money}); err == nil {
+		t.Error("Failed to detect a group with another scale.")
+	}
+
+	degrees, err := parsexp.NewExpression("sin(x)", []string{"x"}, real.New(types.Degrees))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = degrees.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&parsexp.Expression[float64]{Group: real.Real}).UnmarshalBinary(data); err == nil {
+		t.Error("Failed to detect a group with another angle mode.")
+	}
+}
@@ -29,3 +29,14 @@ func (group RunnableMathGroup[T]) MapValues(expression string, interval types.In
 	}
 	return result, nil
 }
+
+// MapGrid parses and evaluates an expression at every point of a grid in a MathGroup.
+// Each axis name of the grid is used as a variable name.
+func (group RunnableMathGroup[T]) MapGrid(expression string, grid types.Grid[T]) (*evaluate.GridResult[T], error) {
+	g := types.MathGroup[T](group)
+	parsedExpression, err := parsexp.ParseVars(expression, grid.Names(), &g)
+	if err != nil {
+		return nil, err
+	}
+	return evaluate.EvaluateGrid(parsedExpression, grid, &g)
+}
@@ -0,0 +1,35 @@
+package types
+
+import "math"
+
+// AngleMode is the unit of the angles read and returned by trigonometric functions.
+type AngleMode int
+
+// The possible angle modes
+const (
+	Radians  AngleMode = iota
+	Degrees            // 360 to a full turn
+	Gradians           // 400 to a full turn
+)
+
+// String returns the name of the angle mode, such as "degrees".
+func (a AngleMode) String() string {
+	switch a {
+	case Degrees:
+		return "degrees"
+	case Gradians:
+		return "gradians"
+	}
+	return "radians"
+}
+
+// Size returns the size of one unit of the angle mode, in radians.
+func (a AngleMode) Size() float64 {
+	switch a {
+	case Degrees:
+		return math.Pi / 180
+	case Gradians:
+		return math.Pi / 200
+	}
+	return 1
+}
@@ -0,0 +1,56 @@
+package types
+
+// Axis is a named interval forming one dimension of a Grid.
+type Axis[T any] struct {
+	Name     string
+	Interval Interval[T]
+}
+
+// Grid represents the Cartesian product of named intervals.
+// The first axis varies slowest, and the last axis varies fastest.
+type Grid[T any] struct {
+	Axes []Axis[T]
+}
+
+// NewGrid constructs a grid from the given axes.
+func NewGrid[T any](axes ...Axis[T]) *Grid[T] {
+	if len(axes) == 0 {
+		panic("Grid must have at least one axis")
+	}
+	seen := map[string]bool{}
+	for _, axis := range axes {
+		if seen[axis.Name] {
+			panic("Duplicate axis " + axis.Name)
+		}
+		seen[axis.Name] = true
+	}
+	return &Grid[T]{Axes: axes}
+}
+
+// Names returns the name of each axis, in order.
+func (g Grid[T]) Names() []string {
+	names := make([]string, len(g.Axes))
+	for i, axis := range g.Axes {
+		names[i] = axis.Name
+	}
+	return names
+}
+
+// Points returns the values along each axis, in order.
+func (g Grid[T]) Points() [][]T {
+	points := make([][]T, len(g.Axes))
+	for i, axis := range g.Axes {
+		points[i] = axis.Interval.Values()
+	}
+	return points
+}
+
+// Shape returns the number of values along each axis.
+func (g Grid[T]) Shape() []int {
+	points := g.Points()
+	shape := make([]int, len(points))
+	for i, p := range points {
+		shape[i] = len(p)
+	}
+	return shape
+}
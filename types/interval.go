@@ -8,3 +8,13 @@ type Interval[T any] struct {
 	// Return Next value in the interval. Done is true if outside of interval
 	Next func(cur T) (next T, done bool)
 }
+
+// Values returns every value in the interval, in order.
+func (i Interval[T]) Values() []T {
+	values := []T{}
+	done := false
+	for current := i.Start; !done; current, done = i.Next(current) {
+		values = append(values, current)
+	}
+	return values
+}
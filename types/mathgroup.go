@@ -1,6 +1,14 @@
 // Package types consists of constants and types representing data relating to a mathematical group/system used for parsing/evaluating
 package types
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
 // Keyword consists of Operators and SingleFunctions
 type Keyword int
 
@@ -14,46 +22,341 @@ const (
 	LParen                          // Left parenthesis
 	RParen                          // Right parenthesis
 	Operator                        // A math operator
-	SingleFunction                  // A one-parameter function
+	SingleFunction                  // A function, called with its arguments in parentheses
+	Comma                           // Separates the arguments of a function
+)
+
+// Fixity represents where an operator is written relative to its operands.
+type Fixity int
+
+// The possible fixities
+const (
+	Infix   Fixity = iota // Between its two operands, like a + b
+	Prefix                // Before its operand, like not a
+	Postfix               // After its operand, like a!
+)
+
+// ErrDivisionByZero is returned when a keyword would divide by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Associativity represents how operators of equal precedence are grouped.
+type Associativity int
+
+// The possible associativities
+const (
+	LeftAssociative  Associativity = iota // a - b - c is (a - b) - c
+	RightAssociative                      // a -> b -> c is a -> (b -> c)
 )
 
 // KeywordData represents data relating to a Keyword.
-// TokenType == Operator: Expect 2 arguments to Apply
-// TokenType == SingleFunction: Expect 1 argument to Apply
+// Apply receives Arity arguments, and returns an error if the keyword is undefined for them.
+// If Arity is 0, it defaults to 2 for infix operators, and 1 for prefix/postfix operators and functions.
+//
+// A keyword with a Condition is a conditional, like if(cond, then, else). It takes 3 arguments,
+// and only the second or third is evaluated, depending on whether Condition holds for the first.
+// If the conditional has a Validate function, it is checked first, and returns an error if the first
+// argument can not be used as a condition.
+//
+// A keyword with an Index is a series, like sum(k, 1, n, k^2). It takes 4 arguments: an index variable,
+// the first and last values of the index, and a body. Index converts the first and last values to integers,
+// and reports whether they are integers. The body is evaluated with the index bound to each integer in turn,
+// as parsed by GetValue. Apply is called with no arguments for the value of an empty series, and then with
+// the running value and each value of the body in turn.
+//
+// A keyword with a Lambda binds variables, like map(v, v^2, list). Its first Binds arguments are variable names,
+// and the argument following them is a body, in which the variables are bound. Lambda is called instead of Apply,
+// with a function that evaluates the body with the variables bound to the given values, and the values of the
+// remaining arguments.
+//
+// An infix operator that is Implicit is also applied between two operands written next to each other,
+// like the multiplication in 3 km.
+//
+// A prefix operator may share its Symbol with an infix operator (like - for negation and subtraction),
+// as long as it is also given its own entry in the keyword string map.
 type KeywordData[T any] struct {
-	Symbol    string
-	TokenType TokenType
-	Apply     func(...T) T
+	Symbol        string
+	TokenType     TokenType
+	Fixity        Fixity        // For operators
+	Associativity Associativity // For infix operators
+	Implicit      bool          // For infix operators
+	Arity         int
+	Apply         func(...T) (T, error)
+	Condition     func(T) bool
+	Validate      func(T) error // For keywords with a Condition
+	Index         func(T) (int, bool)
+	Binds         int // For keywords with a Lambda
+	Lambda        func(body func(...T) (T, error), params ...T) (T, error)
+}
+
+// Infallible adapts a function that is defined for all arguments into an Apply function.
+func Infallible[T any](apply func(...T) T) func(...T) (T, error) {
+	return func(params ...T) (T, error) {
+		return apply(params...), nil
+	}
+}
+
+// Series constructs a series keyword, which combines the values of its body with combine, starting from identity.
+// index converts the first and last values of the index to integers.
+func Series[T any](symbol string, identity T, combine func(T, T) (T, error), index func(T) (int, bool)) KeywordData[T] {
+	return KeywordData[T]{Symbol: symbol, TokenType: SingleFunction, Arity: 4, Index: index,
+		Apply: func(params ...T) (T, error) {
+			if len(params) == 0 {
+				return identity, nil
+			}
+			return combine(params[0], params[1])
+		}}
 }
 
 // MathGroup is a data structure representing a mathematical system.
+// Name identifies the system, such as when exchanging parsed expressions. Groups that are constructed with
+// parameters, such as a precision or angle mode, include them in the name, since they change how
+// expressions are read.
 type MathGroup[T any] struct {
+	Name               string
+	angleMode          AngleMode
 	keywordMap         map[Keyword]KeywordData[T]
 	keywordStringMap   map[string]Keyword
-	operatorPrecedence map[Keyword]int // For operators
+	operatorPrecedence map[Keyword]int   // For operators
+	prefixTokens       map[string]string // Shared prefix operator symbols, and the token to use for them
 	GetValue           func(string) (T, bool)
+	FormatValue        func(T) string
 }
 
 // TODO: Add verification for the three maps
 
+// Option configures a MathGroup when it is constructed.
+type Option[T any] func(*MathGroup[T])
+
+// WithName sets the name of a MathGroup, which is empty by default.
+func WithName[T any](name string) Option[T] {
+	return func(m *MathGroup[T]) {
+		m.Name = name
+	}
+}
+
+// WithFormat sets how a MathGroup formats values, which is with fmt.Sprint by default.
+func WithFormat[T any](formatValue func(T) string) Option[T] {
+	return func(m *MathGroup[T]) {
+		m.FormatValue = formatValue
+	}
+}
+
+// WithAngleMode records the unit of angles used by the trigonometric functions of a MathGroup, which is
+// Radians by default. The functions themselves must be built for the same unit.
+func WithAngleMode[T any](mode AngleMode) Option[T] {
+	return func(m *MathGroup[T]) {
+		m.angleMode = mode
+	}
+}
+
 // NewMathGroup is a constructor for MathGroup
 func NewMathGroup[T any](
 	keywordMap map[Keyword]KeywordData[T],
 	keywordStringMap map[string]Keyword,
 	operatorPrecedence map[Keyword]int,
 	getValue func(string) (T, bool),
+	options ...Option[T],
 ) *MathGroup[T] {
-	return &MathGroup[T]{
+	prefixTokens := map[string]string{}
+	for keyword, keywordData := range keywordMap {
+		isPrefix := keywordData.TokenType == Operator && keywordData.Fixity == Prefix
+		if shared, ok := keywordStringMap[keywordData.Symbol]; isPrefix && ok && shared != keyword {
+			prefixTokens[keywordData.Symbol] = keywordToken(keyword, keywordStringMap)
+		}
+	}
+	m := &MathGroup[T]{
 		keywordMap:         keywordMap,
 		keywordStringMap:   keywordStringMap,
 		operatorPrecedence: operatorPrecedence,
+		prefixTokens:       prefixTokens,
 		GetValue:           getValue,
+		FormatValue:        func(value T) string { return fmt.Sprint(value) },
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Returns the first string, in sorted order, that maps to the given keyword.
+func keywordToken(keyword Keyword, keywordStringMap map[string]Keyword) string {
+	tokens := []string{}
+	for s, k := range keywordStringMap {
+		if k == keyword {
+			tokens = append(tokens, s)
+		}
 	}
+	sort.Strings(tokens)
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[0]
 }
 
 // HasHigherPriority returns true if the current operator has a higher priority.
+// A right associative operator has a higher priority than an equal one preceding it.
 func (m *MathGroup[T]) HasHigherPriority(current Keyword, ref Keyword, refType TokenType) bool {
-	return refType != SingleFunction && m.operatorPrecedence[current] > m.operatorPrecedence[ref]
+	if refType == SingleFunction {
+		return false
+	}
+	if m.Precedence(current) == m.Precedence(ref) {
+		return m.Associativity(current) == RightAssociative
+	}
+	return m.Precedence(current) > m.Precedence(ref)
+}
+
+// AngleMode returns the unit of angles used by the group's trigonometric functions, if it has any.
+func (m *MathGroup[T]) AngleMode() AngleMode {
+	return m.angleMode
+}
+
+// Precedence returns the precedence of an operator. Higher values bind more tightly.
+func (m *MathGroup[T]) Precedence(keyword Keyword) int {
+	return m.operatorPrecedence[keyword]
+}
+
+// Associativity returns the associativity of an operator.
+func (m *MathGroup[T]) Associativity(keyword Keyword) Associativity {
+	return m.keywordMap[keyword].Associativity
+}
+
+// Fixity returns the fixity of an operator.
+func (m *MathGroup[T]) Fixity(keyword Keyword) Fixity {
+	return m.keywordMap[keyword].Fixity
+}
+
+// Arity returns the number of arguments a keyword is applied to.
+func (m *MathGroup[T]) Arity(keyword Keyword) int {
+	keywordData := m.keywordMap[keyword]
+	if keywordData.Arity != 0 {
+		return keywordData.Arity
+	}
+	if keywordData.TokenType == Operator && keywordData.Fixity == Infix {
+		return 2
+	}
+	return 1
+}
+
+// Condition returns the condition of a conditional keyword, or nil if the keyword is not conditional.
+func (m *MathGroup[T]) Condition(keyword Keyword) func(T) bool {
+	return m.keywordMap[keyword].Condition
+}
+
+// Validate returns the check of a conditional keyword's condition, or nil if it has none.
+func (m *MathGroup[T]) Validate(keyword Keyword) func(T) error {
+	return m.keywordMap[keyword].Validate
+}
+
+// Index returns the integer conversion of a series keyword, or nil if the keyword is not a series.
+func (m *MathGroup[T]) Index(keyword Keyword) func(T) (int, bool) {
+	return m.keywordMap[keyword].Index
+}
+
+// Lambda returns the function of a keyword that binds variables, or nil if the keyword does not bind variables.
+func (m *MathGroup[T]) Lambda(keyword Keyword) func(body func(...T) (T, error), params ...T) (T, error) {
+	return m.keywordMap[keyword].Lambda
+}
+
+// Binding returns the number of variables a keyword binds, which are its first arguments, and the position of
+// the argument they are bound in. For keywords that do not bind variables, it returns 0 and -1.
+func (m *MathGroup[T]) Binding(keyword Keyword) (variables int, body int) {
+	keywordData := m.keywordMap[keyword]
+	switch {
+	case keywordData.Index != nil:
+		return 1, 3
+	case keywordData.Lambda != nil:
+		return keywordData.Binds, keywordData.Binds
+	}
+	return 0, -1
+}
+
+// ToInteger converts a value to an integer, like the bounds of the group's series keywords.
+// It returns false if the value is not an integer, or the group has no series keywords.
+func (m *MathGroup[T]) ToInteger(value T) (int, bool) {
+	for _, s := range m.Tokens() {
+		if index := m.keywordMap[m.keywordStringMap[s]].Index; index != nil {
+			return index(value)
+		}
+	}
+	return 0, false
+}
+
+// Tokens returns the token of each keyword in the group, in sorted order.
+func (m *MathGroup[T]) Tokens() []string {
+	tokens := make([]string, 0, len(m.keywordStringMap))
+	for s := range m.keywordStringMap {
+		tokens = append(tokens, s)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// Data returns the data of a keyword, and whether the group has the keyword.
+func (m *MathGroup[T]) Data(keyword Keyword) (KeywordData[T], bool) {
+	keywordData, ok := m.keywordMap[keyword]
+	return keywordData, ok
+}
+
+// ConditionalToken returns the token of a conditional keyword in the group, if there is one.
+func (m *MathGroup[T]) ConditionalToken() (string, bool) {
+	tokens := []string{}
+	for s, keyword := range m.keywordStringMap {
+		if m.keywordMap[keyword].Condition != nil {
+			tokens = append(tokens, s)
+		}
+	}
+	sort.Strings(tokens)
+	if len(tokens) == 0 {
+		return "", false
+	}
+	return tokens[0], true
+}
+
+// ImplicitToken returns the token of the implicit operator in the group, if there is one.
+func (m *MathGroup[T]) ImplicitToken() (string, bool) {
+	tokens := []string{}
+	for s, keyword := range m.keywordStringMap {
+		keywordData := m.keywordMap[keyword]
+		if keywordData.Implicit && keywordData.TokenType == Operator && keywordData.Fixity == Infix {
+			tokens = append(tokens, s)
+		}
+	}
+	sort.Strings(tokens)
+	if len(tokens) == 0 {
+		return "", false
+	}
+	return tokens[0], true
+}
+
+// PrefixToken returns the token to use for s when it is written before an operand.
+// This differs from s only for prefix operators that share their symbol with another keyword.
+func (m *MathGroup[T]) PrefixToken(s string) string {
+	if token, ok := m.prefixTokens[s]; ok {
+		return token
+	}
+	return s
+}
+
+// MatchSymbol returns the longest symbol at the start of s that forms a token by itself.
+// These are punctuation, and operators not made of letters or digits, such as "<=".
+// If there is no such symbol, the empty string is returned.
+func (m *MathGroup[T]) MatchSymbol(s string) string {
+	match := ""
+	for _, symbol := range []string{"(", ")", ",", "{", "}", ";"} {
+		if strings.HasPrefix(s, symbol) {
+			match = symbol
+		}
+	}
+	for symbol, keyword := range m.keywordStringMap {
+		keywordData, ok := m.keywordMap[keyword]
+		if !ok || keywordData.TokenType != Operator || len(symbol) <= len(match) || !strings.HasPrefix(s, symbol) {
+			continue
+		}
+		if strings.IndexFunc(symbol, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			match = symbol
+		}
+	}
+	return match
 }
 
 // KeywordToString converts a keyword into its corresponding string.
@@ -65,7 +368,7 @@ func (m *MathGroup[T]) KeywordToString(keyword Keyword) (s string) {
 }
 
 // ApplyKeyword applies an operation or function on the given arguments.
-func (m *MathGroup[T]) ApplyKeyword(keyword Keyword, args ...T) T {
+func (m *MathGroup[T]) ApplyKeyword(keyword Keyword, args ...T) (T, error) {
 	return m.keywordMap[keyword].Apply(args...)
 }
 
@@ -79,6 +382,9 @@ func (m *MathGroup[T]) StringToTokenType(s string) (TokenType, Keyword) {
 	if s == ")" {
 		return RParen, 0
 	}
+	if s == "," {
+		return Comma, 0
+	}
 
 	keyword, ok := m.keywordStringMap[s]
 	keywordData, ok2 := m.keywordMap[keyword]